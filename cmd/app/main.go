@@ -20,13 +20,17 @@ import (
 	"prservice/internal/app/config"
 	httpapi "prservice/internal/app/http"
 	"prservice/internal/app/http/handler"
+	"prservice/internal/domain"
+	"prservice/internal/domain/eventlog"
 	"prservice/internal/domain/pr"
 	"prservice/internal/domain/stats"
 	"prservice/internal/domain/team"
 	"prservice/internal/domain/user"
+	"prservice/internal/domain/webhook"
 	"prservice/internal/infrastructure/async"
 	"prservice/internal/infrastructure/db/pg"
 	"prservice/internal/infrastructure/logging"
+	webhookhttp "prservice/internal/infrastructure/webhook"
 )
 
 type randSource struct {
@@ -40,6 +44,16 @@ func (rs *randSource) Shuffle(n int, swap func(i, j int)) {
 	rs.mu.Unlock()
 }
 
+func (rs *randSource) Intn(n int) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.r.Intn(n)
+}
+
+func (rs *randSource) WithSeed(seed int64) domain.RandomSource {
+	return &randSource{r: rand.New(rand.NewSource(seed))}
+}
+
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -77,6 +91,19 @@ func main() {
 	eventBus := async.NewAsyncEventBus(ctx, 4, log)
 	defer eventBus.Close()
 
+	webhookSvc := webhook.NewService(uow, pg.NewWebhookRepository(db), webhookhttp.NewHTTPSender(5*time.Second), webhook.DefaultRetryPolicy(), webhook.PublicTargets)
+	dispatcher := async.NewWebhookDispatcher(webhookSvc, time.Second, 20, log)
+	dispatcher.Start(ctx)
+	defer dispatcher.Close()
+
+	subscribers := async.FanoutBus{eventBus, webhookSvc}
+	eventLogSvc := eventlog.NewService(uow, pg.NewEventLogRepository(db), subscribers)
+
+	outbox := pg.NewOutbox(db)
+	relay := async.NewOutboxRelay(uow, outbox, async.FanoutBus{eventLogSvc, webhookSvc}, eventBus, async.DefaultOutboxRetryPolicy(), time.Second, 100, log)
+	relay.Start(ctx)
+	defer relay.Close()
+
 	rnd := &randSource{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
 
 	teamRepo := pg.NewTeamRepository(db)
@@ -84,13 +111,24 @@ func main() {
 	prRepo := pg.NewPRRepository(db)
 	statsRepo := pg.NewStatsRepository(db)
 
-	teamSvc := team.NewService(uow, teamRepo, userRepo, eventBus)
-	userSvc := user.NewService(uow, userRepo, eventBus)
-	prSvc := pr.NewService(uow, prRepo, userRepo, eventBus, rnd)
+	selectors, err := pr.NewSelectors(pr.Strategy(cfg.ReviewerStrategy), map[pr.Strategy]pr.ReviewerSelector{
+		pr.StrategyRandom:      pr.NewRandomSelector(rnd),
+		pr.StrategyRoundRobin:  pr.NewRoundRobinSelector(),
+		pr.StrategyWeighted:    pr.NewWeightedSelector(rnd),
+		pr.StrategyLeastLoaded: pr.NewLeastLoadedSelector(statsRepo, rnd),
+		pr.StrategyRotation:    pr.NewRotationSelector(prRepo, teamRepo, rnd, cfg.RotationWindow),
+	})
+	if err != nil {
+		log.Fatal("reviewer selectors error", zap.Error(err))
+	}
+
+	prSvc := pr.NewService(uow, prRepo, userRepo, teamRepo, outbox, selectors)
+	teamSvc := team.NewService(uow, teamRepo, userRepo, prSvc, outbox)
+	userSvc := user.NewService(uow, userRepo, prSvc, teamSvc, outbox)
 	statsSvc := stats.NewService(statsRepo)
 
-	h := handler.New(teamSvc, userSvc, prSvc, statsSvc, log)
-	router := httpapi.NewRouter(h, log)
+	h := handler.New(teamSvc, userSvc, prSvc, statsSvc, webhookSvc, eventLogSvc, eventBus, log)
+	router := httpapi.NewRouter(h, log, cfg.AdminToken)
 
 	srv := &http.Server{
 		Addr:         cfg.HTTPAddr,
@@ -99,6 +137,7 @@ func main() {
 		WriteTimeout: 5 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	srv.RegisterOnShutdown(eventBus.CloseSubscriptions)
 
 	go func() {
 		log.Info("server starting", zap.String("addr", cfg.HTTPAddr))
@@ -3,11 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 type Config struct {
-	DatabaseURL string
-	HTTPAddr    string
+	DatabaseURL      string
+	HTTPAddr         string
+	ReviewerStrategy string
+	RotationWindow   int
+	AdminToken       string
 }
 
 func Load() (Config, error) {
@@ -21,8 +25,25 @@ func Load() (Config, error) {
 		addr = ":8080"
 	}
 
+	strategy := os.Getenv("REVIEWER_STRATEGY")
+	if strategy == "" {
+		strategy = "random"
+	}
+
+	var rotationWindow int
+	if v := os.Getenv("ROTATION_WINDOW"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Config{}, fmt.Errorf("ROTATION_WINDOW must be a positive integer")
+		}
+		rotationWindow = n
+	}
+
 	return Config{
-		DatabaseURL: dbURL,
-		HTTPAddr:    addr,
+		DatabaseURL:      dbURL,
+		HTTPAddr:         addr,
+		ReviewerStrategy: strategy,
+		RotationWindow:   rotationWindow,
+		AdminToken:       os.Getenv("ADMIN_TOKEN"),
 	}, nil
 }
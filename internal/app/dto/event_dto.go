@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Event struct {
+	Seq        int64           `json:"seq,omitempty"`
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+type EventPage struct {
+	Events     []Event `json:"events"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type ReplayResult struct {
+	Replayed int   `json:"replayed"`
+	FirstSeq int64 `json:"first_seq,omitempty"`
+	LastSeq  int64 `json:"last_seq,omitempty"`
+}
@@ -2,14 +2,36 @@ package dto
 
 import "time"
 
+type Review struct {
+	UserID     string     `json:"user_id"`
+	State      string     `json:"state"`
+	Comment    string     `json:"comment,omitempty"`
+	AssignedAt *time.Time `json:"assignedAt,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	SourceTeam string     `json:"source_team,omitempty"`
+}
+
 type PullRequest struct {
 	PullRequestID     string     `json:"pull_request_id"`
 	PullRequestName   string     `json:"pull_request_name"`
 	AuthorID          string     `json:"author_id"`
 	Status            string     `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
+	Reviews           []Review   `json:"reviews"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time `json:"closedAt,omitempty"`
+	MergeForced       bool       `json:"merge_forced,omitempty"`
+	Files             []string   `json:"files,omitempty"`
+	Labels            []string   `json:"labels,omitempty"`
+	Staffing          *Staffing  `json:"staffing,omitempty"`
+}
+
+type Staffing struct {
+	Requested  int      `json:"requested"`
+	Assigned   int      `json:"assigned"`
+	AtCapacity []string `json:"at_capacity,omitempty"`
+	Reason     string   `json:"reason"`
 }
 
 type PullRequestShort struct {
@@ -18,3 +40,23 @@ type PullRequestShort struct {
 	AuthorID        string `json:"author_id"`
 	Status          string `json:"status"`
 }
+
+type Decision struct {
+	Purpose      string         `json:"purpose,omitempty"`
+	TeamName     string         `json:"team_name"`
+	Strategy     string         `json:"strategy"`
+	Seed         int64          `json:"seed"`
+	Candidates   []string       `json:"candidates"`
+	Max          int            `json:"max"`
+	Scores       map[string]int `json:"scores,omitempty"`
+	Selected     []string       `json:"selected"`
+	Replayed     []string       `json:"replayed"`
+	Reproducible bool           `json:"reproducible"`
+}
+
+type Assignment struct {
+	ID        int64      `json:"id"`
+	Operation string     `json:"operation"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Decisions []Decision `json:"decisions"`
+}
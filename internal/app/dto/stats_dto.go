@@ -1,10 +1,13 @@
 package dto
 
+import "time"
+
 type UserAssignmentStat struct {
 	UserID         string `json:"user_id"`
 	AssignedTotal  int    `json:"assigned_total"`
 	AssignedOpen   int    `json:"assigned_open"`
 	AssignedMerged int    `json:"assigned_merged"`
+	Declined       int    `json:"declined"`
 }
 
 type PRAssignmentStat struct {
@@ -12,7 +15,16 @@ type PRAssignmentStat struct {
 	ReviewerCount int    `json:"reviewer_count"`
 }
 
+type ReviewDecline struct {
+	PullRequestID string    `json:"pull_request_id"`
+	UserID        string    `json:"user_id"`
+	ReplacedBy    string    `json:"replaced_by,omitempty"`
+	Reason        string    `json:"reason"`
+	DeclinedAt    time.Time `json:"declinedAt"`
+}
+
 type StatsResponse struct {
-	PerUser []UserAssignmentStat `json:"per_user,omitempty"`
-	PerPR   []PRAssignmentStat   `json:"per_pr,omitempty"`
+	PerUser  []UserAssignmentStat `json:"per_user,omitempty"`
+	PerPR    []PRAssignmentStat   `json:"per_pr,omitempty"`
+	Declines []ReviewDecline      `json:"declines,omitempty"`
 }
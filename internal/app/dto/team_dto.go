@@ -1,12 +1,50 @@
 package dto
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string   `json:"user_id"`
+	Username       string   `json:"username"`
+	IsActive       bool     `json:"is_active"`
+	ReviewWeight   *int     `json:"review_weight,omitempty"`
+	MaxOpenReviews *int     `json:"max_open_reviews,omitempty"`
+	Expertise      []string `json:"expertise,omitempty"`
+	Seniority      string   `json:"seniority,omitempty"`
 }
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName             string       `json:"team_name"`
+	Members              []TeamMember `json:"members"`
+	ReviewerStrategy     string       `json:"reviewer_strategy,omitempty"`
+	MinReviewers         *int         `json:"min_reviewers,omitempty"`
+	MaxReviewers         *int         `json:"max_reviewers,omitempty"`
+	RequiredApprovals    *int         `json:"required_approvals,omitempty"`
+	ReassignOnDeactivate *bool        `json:"reassign_on_deactivate,omitempty"`
+	PartnerTeams         []string     `json:"partner_teams,omitempty"`
+	MentorPairing        *bool        `json:"mentor_pairing,omitempty"`
+	RotationWindow       *int         `json:"rotation_window,omitempty"`
+}
+
+type OwnershipRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type UnknownOwner struct {
+	Line   int    `json:"line"`
+	Handle string `json:"handle"`
+	Reason string `json:"reason"`
+}
+
+type PathOwners struct {
+	Path    string   `json:"path"`
+	Pattern string   `json:"pattern,omitempty"`
+	Owners  []string `json:"owners"`
+}
+
+type PairRule struct {
+	ID         int64  `json:"id"`
+	TeamName   string `json:"team_name"`
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Kind       string `json:"kind"`
+	Reason     string `json:"reason,omitempty"`
 }
@@ -1,8 +1,29 @@
 package dto
 
+import "time"
+
 type User struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
 }
+
+type Reassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	ReplacedBy    string `json:"replaced_by,omitempty"`
+}
+
+type ReassignReport struct {
+	Reassigned       []Reassignment `json:"reassigned"`
+	WithoutCandidate []Reassignment `json:"without_candidate"`
+}
+
+type Unavailability struct {
+	ID       int64     `json:"id"`
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason,omitempty"`
+}
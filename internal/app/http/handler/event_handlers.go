@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"prservice/internal/app/dto"
+	"prservice/internal/domain"
+	"prservice/internal/domain/eventlog"
+)
+
+const (
+	streamBuffer = 256
+	streamRetry  = 3 * time.Second
+)
+
+func (h *Handler) EventsList(c *gin.Context) {
+	q := eventlog.Query{
+		Cursor:        c.Query("cursor"),
+		Type:          c.Query("type"),
+		PullRequestID: c.Query("pull_request_id"),
+	}
+
+	if v := c.Query("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			h.badRequest(c, "since must be an RFC 3339 timestamp")
+			return
+		}
+		q.Since = &since
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.badRequest(c, "limit must be a positive integer")
+			return
+		}
+		q.Limit = n
+	}
+
+	page, err := h.EventLogSvc.List(c.Request.Context(), q)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := dto.EventPage{
+		Events:     make([]dto.Event, 0, len(page.Records)),
+		NextCursor: page.NextCursor,
+	}
+	for _, r := range page.Records {
+		e := toEventDTO(r.Envelope)
+		e.Seq = r.Seq
+		resp.Events = append(resp.Events, e)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) EventsStream(c *gin.Context) {
+	filter := eventlog.StreamFilter{
+		TeamName: c.Query("team_name"),
+		UserID:   c.Query("user_id"),
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	ctx := c.Request.Context()
+	live, unsubscribe := h.Stream.Subscribe(streamBuffer)
+	defer unsubscribe()
+
+	backlog, err := h.EventLogSvc.StreamBacklog(ctx, lastEventID, filter)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.Log.Warn("event stream: cannot reset write deadline", zap.Error(err))
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	sent := make(map[string]bool, len(backlog))
+	for _, r := range backlog {
+		if err := writeStreamEvent(c.Writer, r.Envelope); err != nil {
+			return
+		}
+		sent[r.Envelope.ID] = true
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case env, ok := <-live:
+			if !ok {
+				return
+			}
+			if sent[env.ID] || !filter.Match(env) {
+				continue
+			}
+			if err := writeStreamEvent(c.Writer, env); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeStreamEvent(w io.Writer, env domain.Envelope) error {
+	data, err := json.Marshal(toEventDTO(env))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", env.ID, env.Type, data)
+	return err
+}
+
+func (h *Handler) AdminEventsReplay(c *gin.Context) {
+	var body struct {
+		FromSeq       int64      `json:"from_seq"`
+		ToSeq         int64      `json:"to_seq"`
+		Since         *time.Time `json:"since"`
+		Until         *time.Time `json:"until"`
+		Type          string     `json:"type"`
+		PullRequestID string     `json:"pull_request_id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+
+	res, err := h.EventLogSvc.Replay(c.Request.Context(), eventlog.ReplayRange{
+		FromSeq:       body.FromSeq,
+		ToSeq:         body.ToSeq,
+		Since:         body.Since,
+		Until:         body.Until,
+		Type:          body.Type,
+		PullRequestID: body.PullRequestID,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReplayResult{
+		Replayed: res.Replayed,
+		FirstSeq: res.FirstSeq,
+		LastSeq:  res.LastSeq,
+	})
+}
+
+func toEventDTO(env domain.Envelope) dto.Event {
+	return dto.Event{
+		ID:         env.ID,
+		Type:       env.Type,
+		Version:    env.Version,
+		OccurredAt: env.OccurredAt,
+		Actor:      env.Actor,
+		Payload:    env.Payload,
+	}
+}
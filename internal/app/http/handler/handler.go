@@ -1,20 +1,30 @@
 package handler
 
 import (
+	"time"
+
+	"prservice/internal/domain"
+	"prservice/internal/domain/eventlog"
 	"prservice/internal/domain/pr"
 	"prservice/internal/domain/stats"
 	"prservice/internal/domain/team"
 	"prservice/internal/domain/user"
+	"prservice/internal/domain/webhook"
 
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	TeamSvc  team.Service
-	UserSvc  user.Service
-	PRSvc    pr.Service
-	StatsSvc stats.Service
-	Log      *zap.Logger
+	TeamSvc     team.Service
+	UserSvc     user.Service
+	PRSvc       pr.Service
+	StatsSvc    stats.Service
+	WebhookSvc  webhook.Service
+	EventLogSvc eventlog.Service
+	Stream      domain.EventStream
+	Log         *zap.Logger
+
+	StreamHeartbeat time.Duration
 }
 
 func New(
@@ -22,13 +32,21 @@ func New(
 	userSvc user.Service,
 	prSvc pr.Service,
 	statsSvc stats.Service,
+	webhookSvc webhook.Service,
+	eventLogSvc eventlog.Service,
+	stream domain.EventStream,
 	log *zap.Logger,
 ) *Handler {
 	return &Handler{
-		TeamSvc:  teamSvc,
-		UserSvc:  userSvc,
-		PRSvc:    prSvc,
-		StatsSvc: statsSvc,
-		Log:      log,
+		TeamSvc:     teamSvc,
+		UserSvc:     userSvc,
+		PRSvc:       prSvc,
+		StatsSvc:    statsSvc,
+		WebhookSvc:  webhookSvc,
+		EventLogSvc: eventLogSvc,
+		Stream:      stream,
+		Log:         log,
+
+		StreamHeartbeat: 15 * time.Second,
 	}
 }
@@ -1,18 +1,23 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"prservice/internal/app/dto"
+	"prservice/internal/domain/pr"
 )
 
 func (h *Handler) PRCreate(c *gin.Context) {
 	var body struct {
-		PullRequestID   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		AuthorID        string `json:"author_id"`
+		PullRequestID   string   `json:"pull_request_id"`
+		PullRequestName string   `json:"pull_request_name"`
+		AuthorID        string   `json:"author_id"`
+		Draft           bool     `json:"draft"`
+		Files           []string `json:"files"`
+		Labels          []string `json:"labels"`
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -25,7 +30,14 @@ func (h *Handler) PRCreate(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.PRSvc.Create(c.Request.Context(), body.PullRequestID, body.PullRequestName, body.AuthorID)
+	res, err := h.PRSvc.Create(c.Request.Context(), pr.CreateParams{
+		ID:       body.PullRequestID,
+		Name:     body.PullRequestName,
+		AuthorID: body.AuthorID,
+		Draft:    body.Draft,
+		Files:    body.Files,
+		Labels:   body.Labels,
+	})
 	if err != nil {
 		h.writeError(c, err)
 		return
@@ -34,15 +46,7 @@ func (h *Handler) PRCreate(c *gin.Context) {
 	resp := struct {
 		PR dto.PullRequest `json:"pr"`
 	}{
-		PR: dto.PullRequest{
-			PullRequestID:     pr.ID,
-			PullRequestName:   pr.Name,
-			AuthorID:          pr.AuthorID,
-			Status:            string(pr.Status),
-			AssignedReviewers: append([]string(nil), pr.AssignedReviewers...),
-			CreatedAt:         pr.CreatedAt,
-			MergedAt:          pr.MergedAt,
-		},
+		PR: toPullRequestDTO(res),
 	}
 
 	c.JSON(http.StatusCreated, resp)
@@ -51,6 +55,7 @@ func (h *Handler) PRCreate(c *gin.Context) {
 func (h *Handler) PRMerge(c *gin.Context) {
 	var body struct {
 		PullRequestID string `json:"pull_request_id"`
+		Force         bool   `json:"force"`
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -63,7 +68,7 @@ func (h *Handler) PRMerge(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.PRSvc.Merge(c.Request.Context(), body.PullRequestID)
+	pr, err := h.PRSvc.Merge(c.Request.Context(), body.PullRequestID, body.Force)
 	if err != nil {
 		h.writeError(c, err)
 		return
@@ -72,15 +77,49 @@ func (h *Handler) PRMerge(c *gin.Context) {
 	resp := struct {
 		PR dto.PullRequest `json:"pr"`
 	}{
-		PR: dto.PullRequest{
-			PullRequestID:     pr.ID,
-			PullRequestName:   pr.Name,
-			AuthorID:          pr.AuthorID,
-			Status:            string(pr.Status),
-			AssignedReviewers: append([]string(nil), pr.AssignedReviewers...),
-			CreatedAt:         pr.CreatedAt,
-			MergedAt:          pr.MergedAt,
-		},
+		PR: toPullRequestDTO(pr),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) PRMarkReady(c *gin.Context) {
+	h.prTransition(c, h.PRSvc.MarkReady)
+}
+
+func (h *Handler) PRClose(c *gin.Context) {
+	h.prTransition(c, h.PRSvc.Close)
+}
+
+func (h *Handler) PRReopen(c *gin.Context) {
+	h.prTransition(c, h.PRSvc.Reopen)
+}
+
+func (h *Handler) prTransition(c *gin.Context, fn func(ctx context.Context, id string) (pr.PullRequest, error)) {
+	var body struct {
+		PullRequestID string `json:"pull_request_id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+
+	if body.PullRequestID == "" {
+		h.badRequest(c, "pull_request_id is required")
+		return
+	}
+
+	res, err := fn(c.Request.Context(), body.PullRequestID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		PR dto.PullRequest `json:"pr"`
+	}{
+		PR: toPullRequestDTO(res),
 	}
 
 	c.JSON(http.StatusOK, resp)
@@ -90,6 +129,7 @@ func (h *Handler) PRReassign(c *gin.Context) {
 	var body struct {
 		PullRequestID string `json:"pull_request_id"`
 		OldUserID     string `json:"old_user_id"`
+		NewUserID     string `json:"new_user_id"`
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -102,7 +142,7 @@ func (h *Handler) PRReassign(c *gin.Context) {
 		return
 	}
 
-	pr, replacedBy, err := h.PRSvc.ReassignReviewer(c.Request.Context(), body.PullRequestID, body.OldUserID)
+	pr, replacedBy, err := h.PRSvc.ReassignReviewer(c.Request.Context(), body.PullRequestID, body.OldUserID, body.NewUserID)
 	if err != nil {
 		h.writeError(c, err)
 		return
@@ -112,17 +152,239 @@ func (h *Handler) PRReassign(c *gin.Context) {
 		PR         dto.PullRequest `json:"pr"`
 		ReplacedBy string          `json:"replaced_by"`
 	}{
-		PR: dto.PullRequest{
-			PullRequestID:     pr.ID,
-			PullRequestName:   pr.Name,
-			AuthorID:          pr.AuthorID,
-			Status:            string(pr.Status),
-			AssignedReviewers: append([]string(nil), pr.AssignedReviewers...),
-			CreatedAt:         pr.CreatedAt,
-			MergedAt:          pr.MergedAt,
-		},
+		PR:         toPullRequestDTO(pr),
+		ReplacedBy: replacedBy,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) PRReview(c *gin.Context) {
+	var body struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+		Action        string `json:"action"`
+		Comment       string `json:"comment"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+
+	if body.PullRequestID == "" || body.UserID == "" {
+		h.badRequest(c, "pull_request_id and user_id are required")
+		return
+	}
+	action := pr.ReviewAction(body.Action)
+	if !action.Valid() {
+		h.badRequest(c, "invalid action, must be one of: APPROVE, REQUEST_CHANGES, COMMENT")
+		return
+	}
+	if action == pr.ReviewActionComment && body.Comment == "" {
+		h.badRequest(c, "comment is required for COMMENT action")
+		return
+	}
+
+	res, err := h.PRSvc.SubmitReview(c.Request.Context(), body.PullRequestID, body.UserID, action, body.Comment)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		PR dto.PullRequest `json:"pr"`
+	}{
+		PR: toPullRequestDTO(res),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) PRDismissReview(c *gin.Context) {
+	var body struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+		Comment       string `json:"comment"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+
+	if body.PullRequestID == "" || body.UserID == "" {
+		h.badRequest(c, "pull_request_id and user_id are required")
+		return
+	}
+
+	res, err := h.PRSvc.DismissReview(c.Request.Context(), body.PullRequestID, body.UserID, body.Comment)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		PR dto.PullRequest `json:"pr"`
+	}{
+		PR: toPullRequestDTO(res),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) PRDecline(c *gin.Context) {
+	var body struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+		Reason        string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+
+	if body.PullRequestID == "" || body.UserID == "" || body.Reason == "" {
+		h.badRequest(c, "pull_request_id, user_id and reason are required")
+		return
+	}
+
+	res, replacedBy, err := h.PRSvc.DeclineReview(c.Request.Context(), body.PullRequestID, body.UserID, body.Reason)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		PR         dto.PullRequest `json:"pr"`
+		ReplacedBy string          `json:"replaced_by,omitempty"`
+	}{
+		PR:         toPullRequestDTO(res),
 		ReplacedBy: replacedBy,
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *Handler) PRAddReviewer(c *gin.Context) {
+	h.prReviewerChange(c, h.PRSvc.AddReviewer)
+}
+
+func (h *Handler) PRRemoveReviewer(c *gin.Context) {
+	h.prReviewerChange(c, h.PRSvc.RemoveReviewer)
+}
+
+func (h *Handler) prReviewerChange(c *gin.Context, fn func(ctx context.Context, prID, userID string) (pr.PullRequest, error)) {
+	var body struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+
+	if body.PullRequestID == "" || body.UserID == "" {
+		h.badRequest(c, "pull_request_id and user_id are required")
+		return
+	}
+
+	res, err := fn(c.Request.Context(), body.PullRequestID, body.UserID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		PR dto.PullRequest `json:"pr"`
+	}{
+		PR: toPullRequestDTO(res),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) PRAssignmentExplain(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		h.badRequest(c, "pull_request_id is required")
+		return
+	}
+
+	assignments, err := h.PRSvc.ExplainAssignment(c.Request.Context(), prID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		PullRequestID string           `json:"pull_request_id"`
+		Assignments   []dto.Assignment `json:"assignments"`
+	}{
+		PullRequestID: prID,
+		Assignments:   make([]dto.Assignment, 0, len(assignments)),
+	}
+	for _, a := range assignments {
+		item := dto.Assignment{
+			ID:        a.ID,
+			Operation: a.Operation,
+			CreatedAt: a.CreatedAt,
+			Decisions: make([]dto.Decision, 0, len(a.Decisions)),
+		}
+		for _, d := range a.Decisions {
+			item.Decisions = append(item.Decisions, dto.Decision{
+				Purpose:      d.Purpose,
+				TeamName:     d.TeamName,
+				Strategy:     string(d.Strategy),
+				Seed:         d.Seed,
+				Candidates:   d.Candidates,
+				Max:          d.Max,
+				Scores:       d.Scores,
+				Selected:     d.Selected,
+				Replayed:     d.Replayed,
+				Reproducible: d.Reproducible,
+			})
+		}
+		resp.Assignments = append(resp.Assignments, item)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toPullRequestDTO(p pr.PullRequest) dto.PullRequest {
+	res := dto.PullRequest{
+		PullRequestID:     p.ID,
+		PullRequestName:   p.Name,
+		AuthorID:          p.AuthorID,
+		Status:            string(p.Status),
+		AssignedReviewers: append([]string(nil), p.AssignedReviewers...),
+		Reviews:           make([]dto.Review, 0, len(p.Reviews)),
+		CreatedAt:         p.CreatedAt,
+		MergedAt:          p.MergedAt,
+		ClosedAt:          p.ClosedAt,
+		MergeForced:       p.MergeForced,
+		Files:             p.Files,
+		Labels:            p.Labels,
+	}
+	for _, r := range p.Reviews {
+		res.Reviews = append(res.Reviews, dto.Review{
+			UserID:     r.ReviewerID,
+			State:      string(r.State),
+			Comment:    r.Comment,
+			AssignedAt: r.AssignedAt,
+			UpdatedAt:  r.UpdatedAt,
+			SourceTeam: r.SourceTeam,
+		})
+	}
+	if p.Staffing != nil {
+		res.Staffing = &dto.Staffing{
+			Requested:  p.Staffing.Requested,
+			Assigned:   p.Staffing.Assigned,
+			AtCapacity: p.Staffing.AtCapacity,
+			Reason:     p.Staffing.Reason,
+		}
+	}
+	return res
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"prservice/internal/app/dto"
+	"prservice/internal/domain/stats"
 )
 
 func (h *Handler) StatsAssignments(c *gin.Context) {
@@ -41,6 +42,7 @@ func (h *Handler) StatsAssignments(c *gin.Context) {
 				AssignedTotal:  s.AssignedTotal,
 				AssignedOpen:   s.AssignedOpen,
 				AssignedMerged: s.AssignedMerged,
+				Declined:       s.Declined,
 			})
 		}
 
@@ -58,6 +60,13 @@ func (h *Handler) StatsAssignments(c *gin.Context) {
 			})
 		}
 
+		declines, err := h.StatsSvc.GetDeclines(ctx, teamName)
+		if err != nil {
+			h.writeError(c, err)
+			return
+		}
+		resp.Declines = toReviewDeclineDTOs(declines)
+
 	case "users":
 		userStats, err := h.StatsSvc.GetUserStats(ctx, teamName)
 		if err != nil {
@@ -72,6 +81,7 @@ func (h *Handler) StatsAssignments(c *gin.Context) {
 				AssignedTotal:  s.AssignedTotal,
 				AssignedOpen:   s.AssignedOpen,
 				AssignedMerged: s.AssignedMerged,
+				Declined:       s.Declined,
 			})
 		}
 
@@ -90,10 +100,32 @@ func (h *Handler) StatsAssignments(c *gin.Context) {
 			})
 		}
 
+	case "declines":
+		declines, err := h.StatsSvc.GetDeclines(ctx, teamName)
+		if err != nil {
+			h.writeError(c, err)
+			return
+		}
+		resp.Declines = toReviewDeclineDTOs(declines)
+
 	default:
-		h.badRequest(c, "invalid scope, must be one of: all, users, prs")
+		h.badRequest(c, "invalid scope, must be one of: all, users, prs, declines")
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func toReviewDeclineDTOs(list []stats.ReviewDecline) []dto.ReviewDecline {
+	res := make([]dto.ReviewDecline, 0, len(list))
+	for _, d := range list {
+		res = append(res, dto.ReviewDecline{
+			PullRequestID: d.PullRequestID,
+			UserID:        d.UserID,
+			ReplacedBy:    d.ReplacedBy,
+			Reason:        d.Reason,
+			DeclinedAt:    d.DeclinedAt,
+		})
+	}
+	return res
+}
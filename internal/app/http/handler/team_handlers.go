@@ -6,7 +6,9 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"prservice/internal/app/dto"
+	"prservice/internal/domain/pr"
 	"prservice/internal/domain/team"
+	"prservice/internal/domain/user"
 )
 
 func (h *Handler) TeamAdd(c *gin.Context) {
@@ -19,16 +21,58 @@ func (h *Handler) TeamAdd(c *gin.Context) {
 		h.badRequest(c, "team_name is required")
 		return
 	}
+	if body.ReviewerStrategy != "" && !pr.Strategy(body.ReviewerStrategy).Valid() {
+		h.badRequest(c, invalidStrategyMsg)
+		return
+	}
+	if body.MaxReviewers != nil && *body.MaxReviewers < 1 {
+		h.badRequest(c, "max_reviewers must be positive")
+		return
+	}
 
 	t := team.Team{
 		Name:    body.TeamName,
 		Members: make([]team.Member, 0, len(body.Members)),
+		Settings: team.DefaultSettings().Apply(team.SettingsUpdate{
+			ReviewerStrategy:     &body.ReviewerStrategy,
+			MinReviewers:         body.MinReviewers,
+			MaxReviewers:         body.MaxReviewers,
+			RequiredApprovals:    body.RequiredApprovals,
+			ReassignOnDeactivate: body.ReassignOnDeactivate,
+			PartnerTeams:         &body.PartnerTeams,
+			MentorPairing:        body.MentorPairing,
+			RotationWindow:       body.RotationWindow,
+		}),
 	}
 	for _, m := range body.Members {
+		weight := 1
+		if m.ReviewWeight != nil {
+			if *m.ReviewWeight < 1 {
+				h.badRequest(c, "review_weight must be positive")
+				return
+			}
+			weight = *m.ReviewWeight
+		}
+		maxOpen := 0
+		if m.MaxOpenReviews != nil {
+			if *m.MaxOpenReviews < 1 {
+				h.badRequest(c, "max_open_reviews must be positive")
+				return
+			}
+			maxOpen = *m.MaxOpenReviews
+		}
+		if m.Seniority != "" && !user.Seniority(m.Seniority).Valid() {
+			h.badRequest(c, "invalid seniority, must be one of: junior, middle, senior")
+			return
+		}
 		t.Members = append(t.Members, team.Member{
-			ID:       m.UserID,
-			Username: m.Username,
-			IsActive: m.IsActive,
+			ID:             m.UserID,
+			Username:       m.Username,
+			IsActive:       m.IsActive,
+			ReviewWeight:   weight,
+			MaxOpenReviews: maxOpen,
+			Expertise:      m.Expertise,
+			Seniority:      user.Seniority(m.Seniority),
 		})
 	}
 
@@ -41,18 +85,7 @@ func (h *Handler) TeamAdd(c *gin.Context) {
 	resp := struct {
 		Team dto.Team `json:"team"`
 	}{
-		Team: dto.Team{
-			TeamName: res.Name,
-			Members:  make([]dto.TeamMember, 0, len(res.Members)),
-		},
-	}
-
-	for _, m := range res.Members {
-		resp.Team.Members = append(resp.Team.Members, dto.TeamMember{
-			UserID:   m.ID,
-			Username: m.Username,
-			IsActive: m.IsActive,
-		})
+		Team: toTeamDTO(res),
 	}
 
 	c.JSON(http.StatusCreated, resp)
@@ -71,16 +104,392 @@ func (h *Handler) TeamGet(c *gin.Context) {
 		return
 	}
 
-	resp := dto.Team{
-		TeamName: res.Name,
-		Members:  make([]dto.TeamMember, 0, len(res.Members)),
+	c.JSON(http.StatusOK, toTeamDTO(res))
+}
+
+func (h *Handler) TeamUpdate(c *gin.Context) {
+	var body struct {
+		TeamName             string    `json:"team_name"`
+		ReviewerStrategy     *string   `json:"reviewer_strategy"`
+		MinReviewers         *int      `json:"min_reviewers"`
+		MaxReviewers         *int      `json:"max_reviewers"`
+		RequiredApprovals    *int      `json:"required_approvals"`
+		ReassignOnDeactivate *bool     `json:"reassign_on_deactivate"`
+		PartnerTeams         *[]string `json:"partner_teams"`
+		MentorPairing        *bool     `json:"mentor_pairing"`
+		RotationWindow       *int      `json:"rotation_window"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.TeamName == "" {
+		h.badRequest(c, "team_name is required")
+		return
+	}
+	if body.ReviewerStrategy != nil && *body.ReviewerStrategy != "" && !pr.Strategy(*body.ReviewerStrategy).Valid() {
+		h.badRequest(c, invalidStrategyMsg)
+		return
+	}
+
+	res, err := h.TeamSvc.UpdateSettings(c.Request.Context(), body.TeamName, team.SettingsUpdate{
+		ReviewerStrategy:     body.ReviewerStrategy,
+		MinReviewers:         body.MinReviewers,
+		MaxReviewers:         body.MaxReviewers,
+		RequiredApprovals:    body.RequiredApprovals,
+		ReassignOnDeactivate: body.ReassignOnDeactivate,
+		PartnerTeams:         body.PartnerTeams,
+		MentorPairing:        body.MentorPairing,
+		RotationWindow:       body.RotationWindow,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		Team dto.Team `json:"team"`
+	}{
+		Team: toTeamDTO(res),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) TeamDeactivateUsers(c *gin.Context) {
+	var body struct {
+		TeamName string   `json:"team_name"`
+		UserIDs  []string `json:"user_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.TeamName == "" {
+		h.badRequest(c, "team_name is required")
+		return
+	}
+	if len(body.UserIDs) == 0 {
+		h.badRequest(c, "user_ids is required")
+		return
+	}
+
+	res, err := h.TeamSvc.DeactivateUsers(c.Request.Context(), body.TeamName, body.UserIDs)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		TeamName     string             `json:"team_name"`
+		Users        []dto.User         `json:"users"`
+		Reassignment dto.ReassignReport `json:"reassignment"`
+	}{
+		TeamName:     body.TeamName,
+		Users:        make([]dto.User, 0, len(res.Users)),
+		Reassignment: *toReassignReportDTO(res.Reassignment),
 	}
-	for _, m := range res.Members {
-		resp.Members = append(resp.Members, dto.TeamMember{
-			UserID:   m.ID,
-			Username: m.Username,
-			IsActive: m.IsActive,
+	for _, u := range res.Users {
+		resp.Users = append(resp.Users, dto.User{
+			UserID:   u.ID,
+			Username: u.Username,
+			TeamName: u.TeamName,
+			IsActive: u.IsActive,
 		})
 	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) TeamGetOwnershipRules(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		h.badRequest(c, "team_name is required")
+		return
+	}
+
+	rules, err := h.TeamSvc.GetOwnershipRules(c.Request.Context(), teamName)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	writeOwnershipRules(c, teamName, rules)
+}
+
+func (h *Handler) TeamSetOwnershipRules(c *gin.Context) {
+	var body struct {
+		TeamName string              `json:"team_name"`
+		Rules    []dto.OwnershipRule `json:"rules"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.TeamName == "" {
+		h.badRequest(c, "team_name is required")
+		return
+	}
+
+	rules := make([]team.OwnershipRule, 0, len(body.Rules))
+	for _, r := range body.Rules {
+		rules = append(rules, team.OwnershipRule{Pattern: r.Pattern, Owners: r.Owners})
+	}
+
+	res, err := h.TeamSvc.SetOwnershipRules(c.Request.Context(), body.TeamName, rules)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	writeOwnershipRules(c, body.TeamName, res)
+}
+
+func (h *Handler) TeamImportCodeowners(c *gin.Context) {
+	var body struct {
+		TeamName string `json:"team_name"`
+		Content  string `json:"content"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.TeamName == "" {
+		h.badRequest(c, "team_name is required")
+		return
+	}
+
+	res, err := h.TeamSvc.ImportCodeowners(c.Request.Context(), body.TeamName, body.Content)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		TeamName      string              `json:"team_name"`
+		Rules         []dto.OwnershipRule `json:"rules"`
+		UnknownOwners []dto.UnknownOwner  `json:"unknown_owners"`
+	}{
+		TeamName:      body.TeamName,
+		Rules:         toOwnershipRuleDTOs(res.Rules),
+		UnknownOwners: make([]dto.UnknownOwner, 0, len(res.Unknown)),
+	}
+	for _, u := range res.Unknown {
+		resp.UnknownOwners = append(resp.UnknownOwners, dto.UnknownOwner{Line: u.Line, Handle: u.Handle, Reason: string(u.Reason)})
+	}
+
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *Handler) TeamLookupOwners(c *gin.Context) {
+	var body struct {
+		TeamName string   `json:"team_name"`
+		Paths    []string `json:"paths"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.TeamName == "" {
+		h.badRequest(c, "team_name is required")
+		return
+	}
+	if len(body.Paths) == 0 {
+		h.badRequest(c, "paths is required")
+		return
+	}
+
+	res, err := h.TeamSvc.LookupOwners(c.Request.Context(), body.TeamName, body.Paths)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		TeamName string           `json:"team_name"`
+		Paths    []dto.PathOwners `json:"paths"`
+	}{
+		TeamName: body.TeamName,
+		Paths:    make([]dto.PathOwners, 0, len(res)),
+	}
+	for _, po := range res {
+		resp.Paths = append(resp.Paths, dto.PathOwners{Path: po.Path, Pattern: po.Pattern, Owners: po.Owners})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) TeamAddPairRule(c *gin.Context) {
+	var body struct {
+		TeamName   string `json:"team_name"`
+		ReviewerID string `json:"reviewer_id"`
+		AuthorID   string `json:"author_id"`
+		Kind       string `json:"kind"`
+		Reason     string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.TeamName == "" || body.ReviewerID == "" || body.AuthorID == "" || body.Kind == "" {
+		h.badRequest(c, "team_name, reviewer_id, author_id and kind are required")
+		return
+	}
+
+	res, err := h.TeamSvc.AddPairRule(c.Request.Context(), team.PairRule{
+		TeamName:   body.TeamName,
+		ReviewerID: body.ReviewerID,
+		AuthorID:   body.AuthorID,
+		Kind:       team.PairRuleKind(body.Kind),
+		Reason:     body.Reason,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		Rule dto.PairRule `json:"rule"`
+	}{
+		Rule: toPairRuleDTO(res),
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *Handler) TeamGetPairRules(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		h.badRequest(c, "team_name is required")
+		return
+	}
+
+	rules, err := h.TeamSvc.ListPairRules(c.Request.Context(), teamName)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	writePairRules(c, teamName, rules)
+}
+
+func (h *Handler) TeamRemovePairRule(c *gin.Context) {
+	var body struct {
+		TeamName string `json:"team_name"`
+		ID       int64  `json:"id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.TeamName == "" || body.ID == 0 {
+		h.badRequest(c, "team_name and id are required")
+		return
+	}
+
+	if err := h.TeamSvc.RemovePairRule(c.Request.Context(), body.TeamName, body.ID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	rules, err := h.TeamSvc.ListPairRules(c.Request.Context(), body.TeamName)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	writePairRules(c, body.TeamName, rules)
+}
+
+func writePairRules(c *gin.Context, teamName string, rules []team.PairRule) {
+	resp := struct {
+		TeamName string         `json:"team_name"`
+		Rules    []dto.PairRule `json:"rules"`
+	}{
+		TeamName: teamName,
+		Rules:    make([]dto.PairRule, 0, len(rules)),
+	}
+	for _, r := range rules {
+		resp.Rules = append(resp.Rules, toPairRuleDTO(r))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toPairRuleDTO(r team.PairRule) dto.PairRule {
+	return dto.PairRule{
+		ID:         r.ID,
+		TeamName:   r.TeamName,
+		ReviewerID: r.ReviewerID,
+		AuthorID:   r.AuthorID,
+		Kind:       string(r.Kind),
+		Reason:     r.Reason,
+	}
+}
+
+func writeOwnershipRules(c *gin.Context, teamName string, rules []team.OwnershipRule) {
+	resp := struct {
+		TeamName string              `json:"team_name"`
+		Rules    []dto.OwnershipRule `json:"rules"`
+	}{
+		TeamName: teamName,
+		Rules:    toOwnershipRuleDTOs(rules),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toOwnershipRuleDTOs(rules []team.OwnershipRule) []dto.OwnershipRule {
+	res := make([]dto.OwnershipRule, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, dto.OwnershipRule{
+			Pattern: r.Pattern,
+			Owners:  append([]string{}, r.Owners...),
+		})
+	}
+	return res
+}
+
+const invalidStrategyMsg = "invalid reviewer_strategy, must be one of: random, round_robin, weighted, least_loaded, rotation"
+
+func toTeamDTO(t team.Team) dto.Team {
+	minReviewers, maxReviewers := t.Settings.MinReviewers, t.Settings.MaxReviewers
+	requiredApprovals := t.Settings.RequiredApprovals
+	reassignOnDeactivate := t.Settings.ReassignOnDeactivate
+	mentorPairing := t.Settings.MentorPairing
+	rotationWindow := t.Settings.RotationWindow
+	res := dto.Team{
+		TeamName:             t.Name,
+		Members:              make([]dto.TeamMember, 0, len(t.Members)),
+		ReviewerStrategy:     t.Settings.ReviewerStrategy,
+		MinReviewers:         &minReviewers,
+		MaxReviewers:         &maxReviewers,
+		RequiredApprovals:    &requiredApprovals,
+		ReassignOnDeactivate: &reassignOnDeactivate,
+		PartnerTeams:         t.Settings.PartnerTeams,
+		MentorPairing:        &mentorPairing,
+		RotationWindow:       &rotationWindow,
+	}
+	for _, m := range t.Members {
+		weight := m.ReviewWeight
+		member := dto.TeamMember{
+			UserID:       m.ID,
+			Username:     m.Username,
+			IsActive:     m.IsActive,
+			ReviewWeight: &weight,
+			Expertise:    m.Expertise,
+			Seniority:    string(m.Seniority),
+		}
+		if m.MaxOpenReviews > 0 {
+			maxOpen := m.MaxOpenReviews
+			member.MaxOpenReviews = &maxOpen
+		}
+		res.Members = append(res.Members, member)
+	}
+	return res
+}
@@ -2,10 +2,13 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"prservice/internal/app/dto"
+	"prservice/internal/domain/user"
 )
 
 func (h *Handler) UserSetIsActive(c *gin.Context) {
@@ -23,14 +26,25 @@ func (h *Handler) UserSetIsActive(c *gin.Context) {
 		return
 	}
 
-	u, err := h.UserSvc.SetUserActive(c.Request.Context(), body.UserID, body.IsActive)
+	var reassign *bool
+	if v := c.Query("reassign"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			h.badRequest(c, "reassign must be a boolean")
+			return
+		}
+		reassign = &parsed
+	}
+
+	u, report, err := h.UserSvc.SetUserActive(c.Request.Context(), body.UserID, body.IsActive, reassign)
 	if err != nil {
 		h.writeError(c, err)
 		return
 	}
 
 	resp := struct {
-		User dto.User `json:"user"`
+		User         dto.User            `json:"user"`
+		Reassignment *dto.ReassignReport `json:"reassignment,omitempty"`
 	}{
 		User: dto.User{
 			UserID:   u.ID,
@@ -39,6 +53,9 @@ func (h *Handler) UserSetIsActive(c *gin.Context) {
 			IsActive: u.IsActive,
 		},
 	}
+	if report != nil {
+		resp.Reassignment = toReassignReportDTO(*report)
+	}
 
 	c.JSON(http.StatusOK, resp)
 }
@@ -75,3 +92,127 @@ func (h *Handler) UserGetReview(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *Handler) UserAddUnavailability(c *gin.Context) {
+	var body struct {
+		UserID   string     `json:"user_id"`
+		StartsAt *time.Time `json:"starts_at"`
+		EndsAt   *time.Time `json:"ends_at"`
+		Reason   string     `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.UserID == "" || body.StartsAt == nil || body.EndsAt == nil {
+		h.badRequest(c, "user_id, starts_at and ends_at are required")
+		return
+	}
+
+	res, err := h.UserSvc.AddUnavailability(c.Request.Context(), user.Unavailability{
+		UserID:   body.UserID,
+		StartsAt: *body.StartsAt,
+		EndsAt:   *body.EndsAt,
+		Reason:   body.Reason,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		Unavailability dto.Unavailability `json:"unavailability"`
+	}{
+		Unavailability: toUnavailabilityDTO(res),
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *Handler) UserGetUnavailability(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		h.badRequest(c, "user_id is required")
+		return
+	}
+
+	list, err := h.UserSvc.ListUnavailability(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.writeUnavailability(c, userID, list)
+}
+
+func (h *Handler) UserRemoveUnavailability(c *gin.Context) {
+	var body struct {
+		UserID string `json:"user_id"`
+		ID     int64  `json:"id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.UserID == "" || body.ID == 0 {
+		h.badRequest(c, "user_id and id are required")
+		return
+	}
+
+	if err := h.UserSvc.RemoveUnavailability(c.Request.Context(), body.UserID, body.ID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	list, err := h.UserSvc.ListUnavailability(c.Request.Context(), body.UserID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.writeUnavailability(c, body.UserID, list)
+}
+
+func (h *Handler) writeUnavailability(c *gin.Context, userID string, list []user.Unavailability) {
+	resp := struct {
+		UserID         string               `json:"user_id"`
+		Unavailability []dto.Unavailability `json:"unavailability"`
+	}{
+		UserID:         userID,
+		Unavailability: make([]dto.Unavailability, 0, len(list)),
+	}
+	for _, u := range list {
+		resp.Unavailability = append(resp.Unavailability, toUnavailabilityDTO(u))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toUnavailabilityDTO(u user.Unavailability) dto.Unavailability {
+	return dto.Unavailability{
+		ID:       u.ID,
+		UserID:   u.UserID,
+		StartsAt: u.StartsAt,
+		EndsAt:   u.EndsAt,
+		Reason:   u.Reason,
+	}
+}
+
+func toReassignReportDTO(r user.ReassignReport) *dto.ReassignReport {
+	return &dto.ReassignReport{
+		Reassigned:       toReassignmentDTOs(r.Reassigned),
+		WithoutCandidate: toReassignmentDTOs(r.WithoutCandidate),
+	}
+}
+
+func toReassignmentDTOs(list []user.Reassignment) []dto.Reassignment {
+	res := make([]dto.Reassignment, 0, len(list))
+	for _, a := range list {
+		res = append(res, dto.Reassignment{
+			PullRequestID: a.PRID,
+			UserID:        a.UserID,
+			ReplacedBy:    a.ReplacedBy,
+		})
+	}
+	return res
+}
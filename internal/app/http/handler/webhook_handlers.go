@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"prservice/internal/app/dto"
+	"prservice/internal/domain/webhook"
+)
+
+func (h *Handler) WebhookSubscribe(c *gin.Context) {
+	var body struct {
+		URL        string   `json:"url"`
+		EventTypes []string `json:"event_types"`
+		Secret     string   `json:"secret"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.URL == "" || body.Secret == "" {
+		h.badRequest(c, "url and secret are required")
+		return
+	}
+
+	sub, err := h.WebhookSvc.Subscribe(c.Request.Context(), webhook.Subscription{
+		URL:        body.URL,
+		EventTypes: body.EventTypes,
+		Secret:     body.Secret,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		Subscription dto.WebhookSubscription `json:"subscription"`
+	}{
+		Subscription: toWebhookSubscriptionDTO(sub),
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *Handler) WebhookList(c *gin.Context) {
+	list, err := h.WebhookSvc.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.writeWebhookSubscriptions(c, list)
+}
+
+func (h *Handler) WebhookUnsubscribe(c *gin.Context) {
+	var body struct {
+		SubscriptionID int64 `json:"subscription_id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.badRequest(c, "invalid JSON")
+		return
+	}
+	if body.SubscriptionID == 0 {
+		h.badRequest(c, "subscription_id is required")
+		return
+	}
+
+	if err := h.WebhookSvc.Unsubscribe(c.Request.Context(), body.SubscriptionID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	list, err := h.WebhookSvc.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.writeWebhookSubscriptions(c, list)
+}
+
+func (h *Handler) WebhookDeliveries(c *gin.Context) {
+	var f webhook.DeliveryFilter
+
+	if v := c.Query("subscription_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			h.badRequest(c, "subscription_id must be an integer")
+			return
+		}
+		f.SubscriptionID = &id
+	}
+	if v := c.Query("status"); v != "" {
+		status := webhook.DeliveryStatus(v)
+		f.Status = &status
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.badRequest(c, "limit must be a positive integer")
+			return
+		}
+		f.Limit = n
+	}
+
+	list, err := h.WebhookSvc.ListDeliveries(c.Request.Context(), f)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := struct {
+		Deliveries []dto.WebhookDelivery `json:"deliveries"`
+	}{
+		Deliveries: make([]dto.WebhookDelivery, 0, len(list)),
+	}
+	for _, d := range list {
+		resp.Deliveries = append(resp.Deliveries, dto.WebhookDelivery{
+			ID:             d.ID,
+			SubscriptionID: d.SubscriptionID,
+			Event:          toEventDTO(d.Event),
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			NextAttemptAt:  d.NextAttemptAt,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) writeWebhookSubscriptions(c *gin.Context, list []webhook.Subscription) {
+	resp := struct {
+		Subscriptions []dto.WebhookSubscription `json:"subscriptions"`
+	}{
+		Subscriptions: make([]dto.WebhookSubscription, 0, len(list)),
+	}
+	for _, s := range list {
+		resp.Subscriptions = append(resp.Subscriptions, toWebhookSubscriptionDTO(s))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toWebhookSubscriptionDTO(s webhook.Subscription) dto.WebhookSubscription {
+	eventTypes := s.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return dto.WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  s.CreatedAt,
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"prservice/internal/domain"
+)
+
+const ActorHeader = "X-Actor"
+
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if actor := strings.TrimSpace(c.GetHeader(ActorHeader)); actor != "" {
+			c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), actor))
+		}
+		c.Next()
+	}
+}
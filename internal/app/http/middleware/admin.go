@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"prservice/internal/app/dto"
+)
+
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminToken guards admin endpoints. An empty token disables them entirely
+// instead of leaving them open.
+func AdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+				Error: dto.Error{
+					Code:    "ADMIN_DISABLED",
+					Message: "admin endpoints are disabled: ADMIN_TOKEN is not set",
+				},
+			})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(AdminTokenHeader)), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error: dto.Error{
+					Code:    "UNAUTHORIZED",
+					Message: "invalid admin token",
+				},
+			})
+			return
+		}
+		c.Next()
+	}
+}
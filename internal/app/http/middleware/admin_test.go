@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newAdminRouter(token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/admin/ping", AdminToken(token), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func doAdmin(r *gin.Engine, header string) int {
+	req := httptest.NewRequest(http.MethodPost, "/admin/ping", nil)
+	if header != "" {
+		req.Header.Set(AdminTokenHeader, header)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAdminToken_EmptyTokenRejects(t *testing.T) {
+	r := newAdminRouter("")
+
+	if code := doAdmin(r, ""); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without header, got %d", code)
+	}
+	if code := doAdmin(r, "anything"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 with header, got %d", code)
+	}
+}
+
+func TestAdminToken_ChecksHeader(t *testing.T) {
+	r := newAdminRouter("secret")
+
+	if code := doAdmin(r, ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without header, got %d", code)
+	}
+	if code := doAdmin(r, "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with wrong token, got %d", code)
+	}
+	if code := doAdmin(r, "secret"); code != http.StatusOK {
+		t.Fatalf("expected 200 with valid token, got %d", code)
+	}
+}
@@ -8,28 +8,62 @@ import (
 	"prservice/internal/app/http/middleware"
 )
 
-func NewRouter(h *handler.Handler, log *zap.Logger) *gin.Engine {
+func NewRouter(h *handler.Handler, log *zap.Logger, adminToken string) *gin.Engine {
 	r := gin.New()
 
 	r.Use(
 		gin.Recovery(),
 		middleware.ZapLogger(log),
 		middleware.ZapRecovery(log),
+		middleware.Actor(),
 	)
 
 	r.GET("/health", h.Health)
 
 	r.POST("/team/add", h.TeamAdd)
 	r.GET("/team/get", h.TeamGet)
+	r.POST("/team/update", h.TeamUpdate)
+	r.POST("/team/deactivateUsers", h.TeamDeactivateUsers)
+	r.GET("/team/getOwnershipRules", h.TeamGetOwnershipRules)
+	r.POST("/team/setOwnershipRules", h.TeamSetOwnershipRules)
+	r.POST("/team/importCodeowners", h.TeamImportCodeowners)
+	r.POST("/team/lookupOwners", h.TeamLookupOwners)
+	r.POST("/team/addPairRule", h.TeamAddPairRule)
+	r.GET("/team/getPairRules", h.TeamGetPairRules)
+	r.POST("/team/removePairRule", h.TeamRemovePairRule)
 
 	r.POST("/users/setIsActive", h.UserSetIsActive)
 	r.GET("/users/getReview", h.UserGetReview)
+	r.POST("/users/addUnavailability", h.UserAddUnavailability)
+	r.GET("/users/getUnavailability", h.UserGetUnavailability)
+	r.POST("/users/removeUnavailability", h.UserRemoveUnavailability)
 
 	r.POST("/pullRequest/create", h.PRCreate)
 	r.POST("/pullRequest/merge", h.PRMerge)
+	r.POST("/pullRequest/markReady", h.PRMarkReady)
+	r.POST("/pullRequest/close", h.PRClose)
+	r.POST("/pullRequest/reopen", h.PRReopen)
 	r.POST("/pullRequest/reassign", h.PRReassign)
+	r.POST("/pullRequest/decline", h.PRDecline)
+	r.POST("/pullRequest/addReviewer", h.PRAddReviewer)
+	r.POST("/pullRequest/removeReviewer", h.PRRemoveReviewer)
+	r.POST("/pullRequest/review", h.PRReview)
+	r.POST("/pullRequest/dismissReview", h.PRDismissReview)
+	r.GET("/pullRequest/assignmentExplain", h.PRAssignmentExplain)
 
 	r.GET("/stats/assignments", h.StatsAssignments)
 
+	webhooks := r.Group("/webhooks", middleware.AdminToken(adminToken))
+	webhooks.POST("/subscribe", h.WebhookSubscribe)
+	webhooks.GET("/list", h.WebhookList)
+	webhooks.POST("/unsubscribe", h.WebhookUnsubscribe)
+	webhooks.GET("/deliveries", h.WebhookDeliveries)
+
+	r.GET("/events", h.EventsList)
+	r.GET("/events/stream", h.EventsStream)
+
+	admin := r.Group("/admin", middleware.AdminToken(adminToken))
+	admin.POST("/events/replay", h.AdminEventsReplay)
+
 	return r
 }
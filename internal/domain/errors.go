@@ -9,6 +9,12 @@ const (
 	ErrorCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	ErrorCodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	ErrorCodeNotFound    ErrorCode = "NOT_FOUND"
+	ErrorCodeBadRequest  ErrorCode = "BAD_REQUEST"
+	ErrorCodeNotApproved ErrorCode = "NOT_APPROVED"
+	ErrorCodePRNotOpen   ErrorCode = "PR_NOT_OPEN"
+
+	ErrorCodeInvalidTransition ErrorCode = "INVALID_TRANSITION"
+	ErrorCodeTooManyReviewers  ErrorCode = "TOO_MANY_REVIEWERS"
 )
 
 type DomainError struct {
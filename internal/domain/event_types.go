@@ -0,0 +1,219 @@
+package domain
+
+import "time"
+
+type PRCreated struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	TeamName        string   `json:"team_name"`
+	Status          string   `json:"status"`
+	Reviewers       []string `json:"reviewers"`
+}
+
+func (PRCreated) EventType() string { return "pr.created" }
+func (PRCreated) EventVersion() int { return 2 }
+
+type PRMerged struct {
+	PullRequestID string   `json:"pull_request_id"`
+	AuthorID      string   `json:"author_id"`
+	TeamName      string   `json:"team_name"`
+	Reviewers     []string `json:"reviewers"`
+	Forced        bool     `json:"forced"`
+}
+
+func (PRMerged) EventType() string { return "pr.merged" }
+func (PRMerged) EventVersion() int { return 2 }
+
+type PRTransition struct {
+	PullRequestID string   `json:"pull_request_id"`
+	AuthorID      string   `json:"author_id"`
+	TeamName      string   `json:"team_name"`
+	Reviewers     []string `json:"reviewers"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+}
+
+type PRMarkedReady struct{ PRTransition }
+
+func (PRMarkedReady) EventType() string { return "pr.ready" }
+func (PRMarkedReady) EventVersion() int { return 2 }
+
+type PRClosed struct{ PRTransition }
+
+func (PRClosed) EventType() string { return "pr.closed" }
+func (PRClosed) EventVersion() int { return 2 }
+
+type PRReopened struct{ PRTransition }
+
+func (PRReopened) EventType() string { return "pr.reopened" }
+func (PRReopened) EventVersion() int { return 2 }
+
+type ReviewerReassigned struct {
+	PullRequestID string `json:"pull_request_id"`
+	AuthorID      string `json:"author_id"`
+	TeamName      string `json:"team_name"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+	Manual        bool   `json:"manual"`
+	Redistributed bool   `json:"redistributed"`
+}
+
+func (ReviewerReassigned) EventType() string { return "pr.reassign" }
+func (ReviewerReassigned) EventVersion() int { return 2 }
+
+type ReviewDeclined struct {
+	PullRequestID string `json:"pull_request_id"`
+	AuthorID      string `json:"author_id"`
+	TeamName      string `json:"team_name"`
+	ReviewerID    string `json:"reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+func (ReviewDeclined) EventType() string { return "pr.review_declined" }
+func (ReviewDeclined) EventVersion() int { return 2 }
+
+type ReviewerUnassigned struct {
+	PullRequestID string `json:"pull_request_id"`
+	AuthorID      string `json:"author_id"`
+	TeamName      string `json:"team_name"`
+	ReviewerID    string `json:"reviewer_id"`
+}
+
+func (ReviewerUnassigned) EventType() string { return "pr.reviewer_unassigned" }
+func (ReviewerUnassigned) EventVersion() int { return 2 }
+
+type ReviewerAdded struct {
+	PullRequestID string `json:"pull_request_id"`
+	AuthorID      string `json:"author_id"`
+	TeamName      string `json:"team_name"`
+	ReviewerID    string `json:"reviewer_id"`
+}
+
+func (ReviewerAdded) EventType() string { return "pr.reviewer_added" }
+func (ReviewerAdded) EventVersion() int { return 2 }
+
+type ReviewerRemoved struct {
+	PullRequestID string `json:"pull_request_id"`
+	AuthorID      string `json:"author_id"`
+	TeamName      string `json:"team_name"`
+	ReviewerID    string `json:"reviewer_id"`
+}
+
+func (ReviewerRemoved) EventType() string { return "pr.reviewer_removed" }
+func (ReviewerRemoved) EventVersion() int { return 2 }
+
+type PRReviewed struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Action        string `json:"action"`
+	State         string `json:"state"`
+}
+
+func (PRReviewed) EventType() string { return "pr.reviewed" }
+func (PRReviewed) EventVersion() int { return 1 }
+
+type ReviewDismissed struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+}
+
+func (ReviewDismissed) EventType() string { return "pr.review_dismissed" }
+func (ReviewDismissed) EventVersion() int { return 1 }
+
+type TeamCreated struct {
+	TeamName  string   `json:"team_name"`
+	MemberIDs []string `json:"member_ids"`
+}
+
+func (TeamCreated) EventType() string { return "team.created" }
+func (TeamCreated) EventVersion() int { return 1 }
+
+type TeamUpdated struct {
+	TeamName             string   `json:"team_name"`
+	ReviewerStrategy     string   `json:"reviewer_strategy,omitempty"`
+	MinReviewers         int      `json:"min_reviewers"`
+	MaxReviewers         int      `json:"max_reviewers"`
+	RequiredApprovals    int      `json:"required_approvals"`
+	ReassignOnDeactivate bool     `json:"reassign_on_deactivate"`
+	PartnerTeams         []string `json:"partner_teams"`
+	MentorPairing        bool     `json:"mentor_pairing"`
+	RotationWindow       int      `json:"rotation_window"`
+}
+
+func (TeamUpdated) EventType() string { return "team.updated" }
+func (TeamUpdated) EventVersion() int { return 1 }
+
+type UsersDeactivated struct {
+	TeamName         string   `json:"team_name"`
+	UserIDs          []string `json:"user_ids"`
+	Reassigned       int      `json:"reassigned"`
+	WithoutCandidate int      `json:"without_candidate"`
+}
+
+func (UsersDeactivated) EventType() string { return "team.users_deactivated" }
+func (UsersDeactivated) EventVersion() int { return 1 }
+
+type OwnershipUpdated struct {
+	TeamName string `json:"team_name"`
+	Rules    int    `json:"rules"`
+}
+
+func (OwnershipUpdated) EventType() string { return "team.ownership_updated" }
+func (OwnershipUpdated) EventVersion() int { return 1 }
+
+type CodeownersImported struct {
+	TeamName      string `json:"team_name"`
+	Rules         int    `json:"rules"`
+	UnknownOwners int    `json:"unknown_owners"`
+}
+
+func (CodeownersImported) EventType() string { return "team.codeowners_imported" }
+func (CodeownersImported) EventVersion() int { return 1 }
+
+type PairRuleAdded struct {
+	TeamName   string `json:"team_name"`
+	RuleID     int64  `json:"rule_id"`
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Kind       string `json:"kind"`
+}
+
+func (PairRuleAdded) EventType() string { return "team.pair_rule_added" }
+func (PairRuleAdded) EventVersion() int { return 1 }
+
+type PairRuleRemoved struct {
+	TeamName string `json:"team_name"`
+	RuleID   int64  `json:"rule_id"`
+}
+
+func (PairRuleRemoved) EventType() string { return "team.pair_rule_removed" }
+func (PairRuleRemoved) EventVersion() int { return 1 }
+
+type UserActivityChanged struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+func (UserActivityChanged) EventType() string { return "user.set_active" }
+func (UserActivityChanged) EventVersion() int { return 1 }
+
+type UnavailabilityAdded struct {
+	UserID           string    `json:"user_id"`
+	UnavailabilityID int64     `json:"unavailability_id"`
+	StartsAt         time.Time `json:"starts_at"`
+	EndsAt           time.Time `json:"ends_at"`
+}
+
+func (UnavailabilityAdded) EventType() string { return "user.unavailability_added" }
+func (UnavailabilityAdded) EventVersion() int { return 1 }
+
+type UnavailabilityRemoved struct {
+	UserID           string `json:"user_id"`
+	UnavailabilityID int64  `json:"unavailability_id"`
+}
+
+func (UnavailabilityRemoved) EventType() string { return "user.unavailability_removed" }
+func (UnavailabilityRemoved) EventVersion() int { return 1 }
@@ -0,0 +1,76 @@
+package eventlog
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+	ReplayBatchSize  = 500
+)
+
+type Record struct {
+	Seq      int64
+	Envelope domain.Envelope
+}
+
+type Filter struct {
+	AfterSeq      int64
+	ToSeq         int64
+	Since         *time.Time
+	Until         *time.Time
+	Type          string
+	Types         []string
+	PullRequestID string
+	Limit         int
+}
+
+type Query struct {
+	Cursor        string
+	Since         *time.Time
+	Type          string
+	PullRequestID string
+	Limit         int
+}
+
+type Page struct {
+	Records    []Record
+	NextCursor string
+}
+
+type ReplayRange struct {
+	FromSeq       int64
+	ToSeq         int64
+	Since         *time.Time
+	Until         *time.Time
+	Type          string
+	PullRequestID string
+}
+
+type ReplayResult struct {
+	Replayed int
+	FirstSeq int64
+	LastSeq  int64
+}
+
+func EncodeCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+func DecodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	seq, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return seq, nil
+}
@@ -0,0 +1,14 @@
+package eventlog
+
+import (
+	"context"
+
+	"prservice/internal/domain"
+)
+
+type Repository interface {
+	Append(ctx context.Context, env domain.Envelope) error
+	List(ctx context.Context, f Filter) ([]Record, error)
+	SeqOf(ctx context.Context, eventID string) (int64, error)
+	PullRequestScope(ctx context.Context, pullRequestID string) (Scope, error)
+}
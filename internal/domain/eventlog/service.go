@@ -0,0 +1,206 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"prservice/internal/domain"
+)
+
+type Service interface {
+	Publish(ctx context.Context, env domain.Envelope) error
+	List(ctx context.Context, q Query) (Page, error)
+	Replay(ctx context.Context, r ReplayRange) (ReplayResult, error)
+	StreamBacklog(ctx context.Context, lastEventID string, f StreamFilter) ([]Record, error)
+}
+
+type service struct {
+	uow    domain.UnitOfWork
+	repo   Repository
+	target domain.EventBus
+}
+
+func NewService(uow domain.UnitOfWork, repo Repository, target domain.EventBus) Service {
+	return &service{
+		uow:    uow,
+		repo:   repo,
+		target: target,
+	}
+}
+
+func (s *service) Publish(ctx context.Context, env domain.Envelope) error {
+	if env.Replayed {
+		return nil
+	}
+	return s.repo.Append(ctx, env)
+}
+
+func (s *service) List(ctx context.Context, q Query) (Page, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 0 || limit > MaxPageLimit {
+		return Page{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "limit must be between 1 and 1000",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	var after int64
+	if q.Cursor != "" {
+		seq, err := DecodeCursor(q.Cursor)
+		if err != nil {
+			return Page{}, &domain.DomainError{
+				Code:       domain.ErrorCodeBadRequest,
+				Message:    "invalid cursor",
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		after = seq
+	}
+
+	records, err := s.repo.List(ctx, Filter{
+		AfterSeq:      after,
+		Since:         q.Since,
+		Type:          q.Type,
+		PullRequestID: q.PullRequestID,
+		Limit:         limit + 1,
+	})
+	if err != nil {
+		return Page{}, err
+	}
+
+	var page Page
+	if len(records) > limit {
+		records = records[:limit]
+		page.NextCursor = EncodeCursor(records[limit-1].Seq)
+	}
+	page.Records = records
+	return page, nil
+}
+
+func (s *service) Replay(ctx context.Context, r ReplayRange) (ReplayResult, error) {
+	if r.FromSeq <= 0 && r.Since == nil {
+		return ReplayResult{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "from_seq or since is required",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if (r.ToSeq > 0 && r.ToSeq < r.FromSeq) || (r.Since != nil && r.Until != nil && r.Until.Before(*r.Since)) {
+		return ReplayResult{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "empty replay range",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	var res ReplayResult
+	after := max(r.FromSeq-1, 0)
+
+	for {
+		var batch []Record
+		err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			batch, err = s.repo.List(ctx, Filter{
+				AfterSeq:      after,
+				ToSeq:         r.ToSeq,
+				Since:         r.Since,
+				Until:         r.Until,
+				Type:          r.Type,
+				PullRequestID: r.PullRequestID,
+				Limit:         ReplayBatchSize,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, rec := range batch {
+				env := rec.Envelope
+				env.Replayed = true
+				if err := s.target.Publish(ctx, env); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return res, err
+		}
+
+		if len(batch) > 0 {
+			if res.FirstSeq == 0 {
+				res.FirstSeq = batch[0].Seq
+			}
+			res.LastSeq = batch[len(batch)-1].Seq
+			res.Replayed += len(batch)
+			after = res.LastSeq
+		}
+		if len(batch) < ReplayBatchSize {
+			return res, nil
+		}
+	}
+}
+
+func (s *service) StreamBacklog(ctx context.Context, lastEventID string, f StreamFilter) ([]Record, error) {
+	if lastEventID == "" {
+		return nil, nil
+	}
+	after, err := s.repo.SeqOf(ctx, lastEventID)
+	if err != nil {
+		var de *domain.DomainError
+		if errors.As(err, &de) && de.Code == domain.ErrorCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var res []Record
+	prScopes := map[string]Scope{}
+	for {
+		batch, err := s.repo.List(ctx, Filter{
+			AfterSeq: after,
+			Types:    StreamEvents,
+			Limit:    ReplayBatchSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range batch {
+			ok, err := s.matchStream(ctx, f, rec.Envelope, prScopes)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				res = append(res, rec)
+			}
+		}
+		if len(batch) < ReplayBatchSize {
+			return res, nil
+		}
+		after = batch[len(batch)-1].Seq
+	}
+}
+
+func (s *service) matchStream(ctx context.Context, f StreamFilter, env domain.Envelope, prScopes map[string]Scope) (bool, error) {
+	if !f.Scoped() || !Legacy(env) {
+		return f.Match(env), nil
+	}
+
+	scope, err := ScopeOf(env)
+	if err != nil {
+		return false, nil
+	}
+	pr, ok := prScopes[scope.PullRequestID]
+	if !ok {
+		pr, err = s.repo.PullRequestScope(ctx, scope.PullRequestID)
+		if err != nil {
+			return false, err
+		}
+		prScopes[scope.PullRequestID] = pr
+	}
+	return f.MatchScope(env.Type, scope.Complete(pr)), nil
+}
@@ -0,0 +1,281 @@
+package eventlog_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"prservice/internal/domain"
+	"prservice/internal/domain/eventlog"
+)
+
+type uowStub struct{}
+
+func (uowStub) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type repoFake struct {
+	records  []eventlog.Record
+	prScopes map[string]eventlog.Scope
+	lookups  int
+}
+
+func (r *repoFake) Append(ctx context.Context, env domain.Envelope) error {
+	for _, rec := range r.records {
+		if rec.Envelope.ID == env.ID {
+			return nil
+		}
+	}
+	r.records = append(r.records, eventlog.Record{Seq: int64(len(r.records) + 1), Envelope: env})
+	return nil
+}
+
+func (r *repoFake) List(ctx context.Context, f eventlog.Filter) ([]eventlog.Record, error) {
+	var res []eventlog.Record
+	for _, rec := range r.records {
+		env := rec.Envelope
+		switch {
+		case rec.Seq <= f.AfterSeq,
+			f.ToSeq > 0 && rec.Seq > f.ToSeq,
+			f.Since != nil && env.OccurredAt.Before(*f.Since),
+			f.Until != nil && !env.OccurredAt.Before(*f.Until),
+			f.Type != "" && env.Type != f.Type,
+			len(f.Types) > 0 && !slices.Contains(f.Types, env.Type):
+			continue
+		}
+		if len(res) == f.Limit {
+			break
+		}
+		res = append(res, rec)
+	}
+	return res, nil
+}
+
+func (r *repoFake) SeqOf(ctx context.Context, eventID string) (int64, error) {
+	for _, rec := range r.records {
+		if rec.Envelope.ID == eventID {
+			return rec.Seq, nil
+		}
+	}
+	return 0, &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "event not found"}
+}
+
+func (r *repoFake) PullRequestScope(ctx context.Context, pullRequestID string) (eventlog.Scope, error) {
+	r.lookups++
+	scope := r.prScopes[pullRequestID]
+	scope.PullRequestID = pullRequestID
+	return scope, nil
+}
+
+type busFake struct{ published []domain.Envelope }
+
+func (b *busFake) Publish(ctx context.Context, env domain.Envelope) error {
+	b.published = append(b.published, env)
+	return nil
+}
+
+func seed(t *testing.T, svc eventlog.Service, n int, base time.Time) {
+	t.Helper()
+	for i := 1; i <= n; i++ {
+		typ := "pr.created"
+		if i%2 == 0 {
+			typ = "pr.merged"
+		}
+		err := svc.Publish(context.Background(), domain.Envelope{
+			ID:         fmt.Sprintf("ev-%d", i),
+			Type:       typ,
+			Version:    1,
+			OccurredAt: base.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+}
+
+func TestList_CursorPagination(t *testing.T) {
+	repo := &repoFake{}
+	svc := eventlog.NewService(uowStub{}, repo, &busFake{})
+	ctx := context.Background()
+	seed(t, svc, 5, time.Now())
+
+	var seen []string
+	q := eventlog.Query{Limit: 2}
+	for page := 0; ; page++ {
+		p, err := svc.List(ctx, q)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, r := range p.Records {
+			seen = append(seen, r.Envelope.ID)
+		}
+		if p.NextCursor == "" {
+			break
+		}
+		if page > 5 {
+			t.Fatalf("pagination does not terminate")
+		}
+		q.Cursor = p.NextCursor
+	}
+	if fmt.Sprint(seen) != "[ev-1 ev-2 ev-3 ev-4 ev-5]" {
+		t.Fatalf("unexpected pages %v", seen)
+	}
+
+	p, err := svc.List(ctx, eventlog.Query{Type: "pr.merged"})
+	if err != nil || len(p.Records) != 2 || p.NextCursor != "" {
+		t.Fatalf("unexpected filtered page %+v %v", p, err)
+	}
+
+	for _, q := range []eventlog.Query{{Cursor: "%%%"}, {Limit: eventlog.MaxPageLimit + 1}} {
+		_, err := svc.List(ctx, q)
+		var de *domain.DomainError
+		if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+			t.Fatalf("expected BAD_REQUEST for %+v, got %v", q, err)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	repo := &repoFake{}
+	bus := &busFake{}
+	svc := eventlog.NewService(uowStub{}, repo, bus)
+	ctx := context.Background()
+	base := time.Now()
+	seed(t, svc, 6, base)
+
+	res, err := svc.Replay(ctx, eventlog.ReplayRange{FromSeq: 2, ToSeq: 5, Type: "pr.merged"})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if res.Replayed != 2 || res.FirstSeq != 2 || res.LastSeq != 4 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if len(bus.published) != 2 || !bus.published[0].Replayed || bus.published[0].ID != "ev-2" || bus.published[1].ID != "ev-4" {
+		t.Fatalf("unexpected replayed events %+v", bus.published)
+	}
+
+	since := base.Add(5 * time.Minute)
+	res, err = svc.Replay(ctx, eventlog.ReplayRange{Since: &since})
+	if err != nil || res.Replayed != 2 || res.FirstSeq != 5 {
+		t.Fatalf("unexpected replay since: %+v %v", res, err)
+	}
+
+	if err := svc.Publish(ctx, bus.published[0]); err != nil || len(repo.records) != 6 {
+		t.Fatalf("replayed events must not be appended again")
+	}
+
+	for _, r := range []eventlog.ReplayRange{{}, {FromSeq: 5, ToSeq: 2}} {
+		_, err := svc.Replay(ctx, r)
+		var de *domain.DomainError
+		if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+			t.Fatalf("expected BAD_REQUEST for %+v, got %v", r, err)
+		}
+	}
+}
+
+func TestStreamBacklog(t *testing.T) {
+	repo := &repoFake{}
+	svc := eventlog.NewService(uowStub{}, repo, &busFake{})
+	ctx := context.Background()
+
+	events := []struct {
+		id      string
+		typ     string
+		payload string
+	}{
+		{"ev-1", "pr.created", `{"pull_request_id":"pr-1","author_id":"u1","team_name":"backend","reviewers":["u2"]}`},
+		{"ev-2", "team.created", `{"team_name":"backend","member_ids":["u1","u2","u3"]}`},
+		{"ev-3", "pr.reassign", `{"pull_request_id":"pr-2","author_id":"u4","team_name":"frontend","old_reviewer_id":"u2","new_reviewer_id":"u3"}`},
+		{"ev-4", "pr.merged", `{"pull_request_id":"pr-1","author_id":"u1","team_name":"backend","reviewers":["u3"]}`},
+	}
+	for _, e := range events {
+		if err := svc.Publish(ctx, domain.Envelope{ID: e.id, Type: e.typ, Version: 2, Payload: []byte(e.payload)}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	ids := func(records []eventlog.Record) string {
+		var res []string
+		for _, r := range records {
+			res = append(res, r.Envelope.ID)
+		}
+		return fmt.Sprint(res)
+	}
+
+	cases := []struct {
+		last   string
+		filter eventlog.StreamFilter
+		want   string
+	}{
+		{"ev-1", eventlog.StreamFilter{}, "[ev-3 ev-4]"},
+		{"ev-1", eventlog.StreamFilter{UserID: "u3"}, "[ev-3 ev-4]"},
+		{"ev-1", eventlog.StreamFilter{TeamName: "backend"}, "[ev-4]"},
+		{"ev-3", eventlog.StreamFilter{UserID: "u2"}, "[]"},
+		{"", eventlog.StreamFilter{}, "[]"},
+		{"unknown", eventlog.StreamFilter{}, "[]"},
+	}
+	for _, c := range cases {
+		records, err := svc.StreamBacklog(ctx, c.last, c.filter)
+		if err != nil {
+			t.Fatalf("backlog: %v", err)
+		}
+		if got := ids(records); got != c.want {
+			t.Fatalf("backlog after %q with %+v: got %s, want %s", c.last, c.filter, got, c.want)
+		}
+	}
+}
+
+func TestStreamBacklog_LegacyEvents(t *testing.T) {
+	repo := &repoFake{prScopes: map[string]eventlog.Scope{
+		"pr-1": {AuthorID: "u1", TeamName: "backend", Reviewers: []string{"u2"}},
+	}}
+	svc := eventlog.NewService(uowStub{}, repo, &busFake{})
+	ctx := context.Background()
+
+	events := []struct {
+		id      string
+		typ     string
+		version int
+		payload string
+	}{
+		{"ev-1", "team.created", 1, `{"team_name":"backend","member_ids":["u1","u2"]}`},
+		{"ev-2", "pr.merged", 1, `{"pull_request_id":"pr-1","forced":false}`},
+		{"ev-3", "pr.reassign", 1, `{"pull_request_id":"pr-1","old_reviewer_id":"u2","new_reviewer_id":"u3"}`},
+		{"ev-4", "pr.merged", 2, `{"pull_request_id":"pr-2","author_id":"u4","team_name":"frontend","reviewers":[]}`},
+	}
+	for _, e := range events {
+		if err := svc.Publish(ctx, domain.Envelope{ID: e.id, Type: e.typ, Version: e.version, Payload: []byte(e.payload)}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	cases := []struct {
+		filter eventlog.StreamFilter
+		want   []string
+	}{
+		{eventlog.StreamFilter{TeamName: "backend"}, []string{"ev-2", "ev-3"}},
+		{eventlog.StreamFilter{UserID: "u1"}, []string{"ev-2", "ev-3"}},
+		{eventlog.StreamFilter{UserID: "u3"}, []string{"ev-3"}},
+		{eventlog.StreamFilter{TeamName: "frontend"}, []string{"ev-4"}},
+	}
+	for _, c := range cases {
+		records, err := svc.StreamBacklog(ctx, "ev-1", c.filter)
+		if err != nil {
+			t.Fatalf("backlog: %v", err)
+		}
+		var got []string
+		for _, r := range records {
+			got = append(got, r.Envelope.ID)
+		}
+		if !slices.Equal(got, c.want) {
+			t.Fatalf("backlog with %+v: got %v, want %v", c.filter, got, c.want)
+		}
+	}
+	if repo.lookups != len(cases) {
+		t.Fatalf("expected one pull request lookup per backlog, got %d", repo.lookups)
+	}
+}
@@ -0,0 +1,101 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"slices"
+
+	"prservice/internal/domain"
+)
+
+var StreamEvents = []string{
+	domain.PRCreated{}.EventType(),
+	domain.PRMarkedReady{}.EventType(),
+	domain.PRReopened{}.EventType(),
+	domain.PRMerged{}.EventType(),
+	domain.ReviewerReassigned{}.EventType(),
+	domain.ReviewDeclined{}.EventType(),
+	domain.ReviewerAdded{}.EventType(),
+	domain.ReviewerRemoved{}.EventType(),
+	domain.ReviewerUnassigned{}.EventType(),
+}
+
+// ScopedVersion is the first payload version of the stream events that
+// carries author_id and team_name. Older events only name the pull request
+// and get the rest from its current state.
+const ScopedVersion = 2
+
+type StreamFilter struct {
+	TeamName string
+	UserID   string
+}
+
+type Scope struct {
+	PullRequestID string   `json:"pull_request_id"`
+	TeamName      string   `json:"team_name"`
+	AuthorID      string   `json:"author_id"`
+	Reviewers     []string `json:"reviewers"`
+	ReviewerID    string   `json:"reviewer_id"`
+	OldReviewerID string   `json:"old_reviewer_id"`
+	NewReviewerID string   `json:"new_reviewer_id"`
+}
+
+func ScopeOf(env domain.Envelope) (Scope, error) {
+	var scope Scope
+	err := json.Unmarshal(env.Payload, &scope)
+	return scope, err
+}
+
+// Legacy reports whether the event predates ScopedVersion and needs its
+// scope completed from the pull request.
+func Legacy(env domain.Envelope) bool {
+	return env.Version < ScopedVersion && slices.Contains(StreamEvents, env.Type)
+}
+
+// Complete fills what a legacy payload lacks from the pull request scope.
+func (s Scope) Complete(pr Scope) Scope {
+	if s.TeamName == "" {
+		s.TeamName = pr.TeamName
+	}
+	if s.AuthorID == "" {
+		s.AuthorID = pr.AuthorID
+	}
+	if len(s.Reviewers) == 0 {
+		s.Reviewers = pr.Reviewers
+	}
+	return s
+}
+
+func (f StreamFilter) Scoped() bool {
+	return f.TeamName != "" || f.UserID != ""
+}
+
+func (f StreamFilter) Match(env domain.Envelope) bool {
+	if !slices.Contains(StreamEvents, env.Type) {
+		return false
+	}
+	if !f.Scoped() {
+		return true
+	}
+
+	scope, err := ScopeOf(env)
+	if err != nil {
+		return false
+	}
+	return f.MatchScope(env.Type, scope)
+}
+
+func (f StreamFilter) MatchScope(eventType string, scope Scope) bool {
+	if !slices.Contains(StreamEvents, eventType) {
+		return false
+	}
+	if f.TeamName != "" && scope.TeamName != f.TeamName {
+		return false
+	}
+	if f.UserID != "" {
+		involved := append([]string{scope.AuthorID, scope.ReviewerID, scope.OldReviewerID, scope.NewReviewerID}, scope.Reviewers...)
+		if !slices.Contains(involved, f.UserID) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,12 +1,144 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
-type Event struct {
-	Type    string
-	Payload map[string]any
+const DefaultActor = "system"
+
+type Event interface {
+	EventType() string
+	EventVersion() int
+}
+
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor"`
+	Payload    json.RawMessage `json:"payload"`
+	Replayed   bool            `json:"replayed,omitempty"`
+}
+
+func NewEnvelope(ctx context.Context, e Event) (Envelope, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return Envelope{}, err
+	}
+	id, err := newEventID()
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		ID:         id,
+		Type:       e.EventType(),
+		Version:    e.EventVersion(),
+		OccurredAt: time.Now().UTC(),
+		Actor:      ActorFrom(ctx),
+		Payload:    payload,
+	}, nil
+}
+
+func newEventID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// BatchPublisher is an EventPublisher that can record several events in
+// one round trip.
+type BatchPublisher interface {
+	EventPublisher
+	PublishAll(ctx context.Context, events []Event) error
+}
+
+// PublishAll records events in order, in a single call when p supports it.
+func PublishAll(ctx context.Context, p EventPublisher, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if b, ok := p.(BatchPublisher); ok {
+		return b.PublishAll(ctx, events)
+	}
+	for _, e := range events {
+		if err := p.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type EventBus interface {
-	Publish(ctx context.Context, e Event)
+	Publish(ctx context.Context, env Envelope) error
+}
+
+type EventStream interface {
+	Subscribe(buffer int) (<-chan Envelope, func())
+}
+
+// AggregateOf returns the key that orders events in the outbox: events of
+// one pull request, team or user are delivered in the order they were
+// recorded.
+func AggregateOf(env Envelope) string {
+	kind, _, _ := strings.Cut(env.Type, ".")
+
+	var key struct {
+		PullRequestID string `json:"pull_request_id"`
+		TeamName      string `json:"team_name"`
+		UserID        string `json:"user_id"`
+	}
+	_ = json.Unmarshal(env.Payload, &key)
+
+	switch kind {
+	case "pr":
+		return kind + ":" + key.PullRequestID
+	case "team":
+		return kind + ":" + key.TeamName
+	case "user":
+		return kind + ":" + key.UserID
+	}
+	return kind
+}
+
+type OutboxMessage struct {
+	ID       int64
+	Envelope Envelope
+	Attempts int
+}
+
+type OutboxStore interface {
+	// ClaimNext locks the oldest due message whose aggregate has no earlier
+	// undelivered message; dead-lettered messages no longer hold the
+	// aggregate back. It must be called inside a transaction.
+	ClaimNext(ctx context.Context) (OutboxMessage, bool, error)
+	MarkDispatched(ctx context.Context, id int64) error
+	ScheduleRetry(ctx context.Context, id int64, reason string, delay time.Duration) error
+	MarkDead(ctx context.Context, id int64, reason string) error
 }
@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"prservice/internal/domain"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	ctx := domain.WithActor(context.Background(), "alice")
+
+	env, err := domain.NewEnvelope(ctx, domain.ReviewerReassigned{
+		PullRequestID: "pr-1",
+		OldReviewerID: "u2",
+		NewReviewerID: "u3",
+	})
+	if err != nil {
+		t.Fatalf("envelope: %v", err)
+	}
+	if env.Type != "pr.reassign" || env.Version != 2 || env.Actor != "alice" || env.OccurredAt.IsZero() {
+		t.Fatalf("unexpected envelope %+v", env)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(env.ID) {
+		t.Fatalf("unexpected event id %q", env.ID)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(env.Payload, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload["pull_request_id"] != "pr-1" || payload["old_reviewer_id"] != "u2" || payload["new_reviewer_id"] != "u3" {
+		t.Fatalf("unexpected payload %s", env.Payload)
+	}
+
+	other, err := domain.NewEnvelope(context.Background(), domain.PRClosed{PRTransition: domain.PRTransition{PullRequestID: "pr-1"}})
+	if err != nil {
+		t.Fatalf("envelope: %v", err)
+	}
+	if other.ID == env.ID || other.Actor != domain.DefaultActor || other.Type != "pr.closed" {
+		t.Fatalf("unexpected envelope %+v", other)
+	}
+	if err := json.Unmarshal(other.Payload, &payload); err != nil || payload["pull_request_id"] != "pr-1" {
+		t.Fatalf("embedded transition fields must be flattened, got %s", other.Payload)
+	}
+}
+
+func TestAggregateOf(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		event domain.Event
+		want  string
+	}{
+		{domain.PRMerged{PullRequestID: "pr-1", TeamName: "backend"}, "pr:pr-1"},
+		{domain.PRClosed{PRTransition: domain.PRTransition{PullRequestID: "pr-2"}}, "pr:pr-2"},
+		{domain.TeamUpdated{TeamName: "backend"}, "team:backend"},
+		{domain.UserActivityChanged{UserID: "u1", TeamName: "backend"}, "user:u1"},
+	}
+	for _, c := range cases {
+		env, err := domain.NewEnvelope(ctx, c.event)
+		if err != nil {
+			t.Fatalf("envelope: %v", err)
+		}
+		if got := domain.AggregateOf(env); got != c.want {
+			t.Fatalf("%s: expected aggregate %q, got %q", env.Type, c.want, got)
+		}
+	}
+}
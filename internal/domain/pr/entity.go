@@ -5,18 +5,127 @@ import "time"
 type Status string
 
 const (
+	StatusDraft  Status = "DRAFT"
 	StatusOpen   Status = "OPEN"
 	StatusMerged Status = "MERGED"
+	StatusClosed Status = "CLOSED"
 )
 
+var transitions = map[Status][]Status{
+	StatusDraft:  {StatusOpen, StatusClosed},
+	StatusOpen:   {StatusMerged, StatusClosed},
+	StatusClosed: {StatusOpen},
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, st := range transitions[s] {
+		if st == next {
+			return true
+		}
+	}
+	return false
+}
+
+type ReviewState string
+
+const (
+	ReviewStatePending          ReviewState = "PENDING"
+	ReviewStateApproved         ReviewState = "APPROVED"
+	ReviewStateChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewStateDismissed        ReviewState = "DISMISSED"
+)
+
+type ReviewAction string
+
+const (
+	ReviewActionApprove        ReviewAction = "APPROVE"
+	ReviewActionRequestChanges ReviewAction = "REQUEST_CHANGES"
+	ReviewActionComment        ReviewAction = "COMMENT"
+)
+
+func (a ReviewAction) Valid() bool {
+	switch a {
+	case ReviewActionApprove, ReviewActionRequestChanges, ReviewActionComment:
+		return true
+	}
+	return false
+}
+
+type Review struct {
+	ReviewerID string
+	State      ReviewState
+	Comment    string
+	AssignedAt *time.Time
+	UpdatedAt  *time.Time
+	SourceTeam string
+}
+
 type PullRequest struct {
 	ID                string
 	Name              string
 	AuthorID          string
 	Status            Status
 	AssignedReviewers []string
+	Reviews           []Review
 	CreatedAt         *time.Time
 	MergedAt          *time.Time
+	ClosedAt          *time.Time
+	MergeForced       bool
+	Files             []string
+	Labels            []string
+	Staffing          *Staffing
+}
+
+type Staffing struct {
+	Requested  int
+	Assigned   int
+	AtCapacity []string
+	Reason     string
+}
+
+type Decline struct {
+	PRID       string
+	UserID     string
+	ReplacedBy string
+	Reason     string
+}
+
+// ReviewerChange replaces one reviewer of a PR; an empty NewUserID only
+// removes the old one.
+type ReviewerChange struct {
+	PRID      string
+	OldUserID string
+	NewUserID string
+}
+
+type Decision struct {
+	Purpose      string
+	TeamName     string
+	Strategy     Strategy
+	Seed         int64
+	Candidates   []string
+	Max          int
+	Scores       map[string]int
+	Selected     []string
+	Replayed     []string
+	Reproducible bool
+}
+
+type Assignment struct {
+	ID        int64
+	PRID      string
+	Operation string
+	Decisions []Decision
+	CreatedAt time.Time
+}
+
+type CreateParams struct {
+	ID       string
+	Name     string
+	AuthorID string
+	Draft    bool
+	Files    []string
+	Labels   []string
 }
 
 type PullRequestShort struct {
@@ -4,10 +4,23 @@ import "context"
 
 type Repository interface {
 	CreateWithReviewers(ctx context.Context, pr PullRequest) (PullRequest, error)
+	GetByID(ctx context.Context, id string) (PullRequest, error)
 	LockByID(ctx context.Context, id string) (PullRequest, error)
-	UpdateStatusMerged(ctx context.Context, id string) (PullRequest, error)
+	LockOpenByReviewers(ctx context.Context, userIDs []string) ([]PullRequest, error)
+	UpdateStatusMerged(ctx context.Context, id string, forced bool) (PullRequest, error)
+	UpdateStatus(ctx context.Context, id string, status Status) (PullRequest, error)
 	GetReviewers(ctx context.Context, prID string) ([]string, error)
 	SetReviewers(ctx context.Context, prID string, reviewerIDs []string) error
+	ReplaceReviewers(ctx context.Context, changes []ReviewerChange) error
 	UserIsReviewer(ctx context.Context, prID, userID string) (bool, error)
 	GetUserPRs(ctx context.Context, userID string) ([]PullRequestShort, error)
+	GetReviews(ctx context.Context, prID string) ([]Review, error)
+	SetReviewState(ctx context.Context, prID, userID string, state ReviewState, comment string) error
+	RecordDecline(ctx context.Context, d Decline) error
+	GetDeclinedUsers(ctx context.Context, prID string) ([]string, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	RecentReviewers(ctx context.Context, authorID string, limit int) ([][]string, error)
+	RecordAssignment(ctx context.Context, a Assignment) error
+	RecordAssignments(ctx context.Context, assignments []Assignment) error
+	GetAssignments(ctx context.Context, prID string) ([]Assignment, error)
 }
@@ -0,0 +1,438 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"prservice/internal/domain"
+	"prservice/internal/domain/stats"
+	"prservice/internal/domain/team"
+	"prservice/internal/domain/user"
+)
+
+type Strategy string
+
+const (
+	StrategyRandom      Strategy = "random"
+	StrategyRoundRobin  Strategy = "round_robin"
+	StrategyWeighted    Strategy = "weighted"
+	StrategyLeastLoaded Strategy = "least_loaded"
+	StrategyRotation    Strategy = "rotation"
+)
+
+const DefaultRotationWindow = 10
+
+func (s Strategy) Valid() bool {
+	switch s {
+	case StrategyRandom, StrategyRoundRobin, StrategyWeighted, StrategyLeastLoaded, StrategyRotation:
+		return true
+	}
+	return false
+}
+
+type SelectionRequest struct {
+	TeamName   string
+	AuthorID   string
+	Candidates []user.User
+	Max        int
+	Purpose    string
+}
+
+type ReviewerSelector interface {
+	Select(ctx context.Context, req SelectionRequest) ([]string, error)
+}
+
+type Selectors struct {
+	defaultStrategy Strategy
+	byStrategy      map[Strategy]ReviewerSelector
+}
+
+func NewSelectors(defaultStrategy Strategy, byStrategy map[Strategy]ReviewerSelector) (Selectors, error) {
+	if _, ok := byStrategy[defaultStrategy]; !ok {
+		return Selectors{}, fmt.Errorf("no selector registered for default strategy %q", defaultStrategy)
+	}
+	return Selectors{
+		defaultStrategy: defaultStrategy,
+		byStrategy:      byStrategy,
+	}, nil
+}
+
+func (s Selectors) For(strategy Strategy) ReviewerSelector {
+	if _, ok := s.byStrategy[strategy]; !ok {
+		strategy = s.defaultStrategy
+	}
+	sel := s.byStrategy[strategy]
+	if r, ok := sel.(ReplayableSelector); ok {
+		return &recordingSelector{strategy: strategy, inner: r}
+	}
+	return sel
+}
+
+func (s Selectors) replayer(strategy Strategy) (ReplayableSelector, bool) {
+	r, ok := s.byStrategy[strategy].(ReplayableSelector)
+	return r, ok
+}
+
+type ReplayableSelector interface {
+	ReviewerSelector
+	// NextSeed draws the seed for the next selection from the injected source.
+	NextSeed() int64
+	// Deterministic reports whether Replay with a recorded seed repeats the
+	// original selection.
+	Deterministic() bool
+	SelectSeeded(ctx context.Context, req SelectionRequest, seed int64) ([]string, map[string]int, error)
+	Replay(req SelectionRequest, scores map[string]int, seed int64) []string
+}
+
+type recordingSelector struct {
+	strategy Strategy
+	inner    ReplayableSelector
+}
+
+func (s *recordingSelector) Select(ctx context.Context, req SelectionRequest) ([]string, error) {
+	seed := s.inner.NextSeed()
+	selected, scores, err := s.inner.SelectSeeded(ctx, req, seed)
+	if err != nil {
+		return nil, err
+	}
+	if log, ok := ctx.Value(decisionLogKey{}).(*decisionLog); ok && len(req.Candidates) > 0 && req.Max > 0 {
+		log.decisions = append(log.decisions, Decision{
+			Purpose:    req.Purpose,
+			TeamName:   req.TeamName,
+			Strategy:   s.strategy,
+			Seed:       seed,
+			Candidates: candidateIDs(req.Candidates),
+			Max:        req.Max,
+			Scores:     scores,
+			Selected:   selected,
+		})
+	}
+	return selected, nil
+}
+
+type decisionLogKey struct{}
+
+type decisionLog struct {
+	decisions []Decision
+}
+
+func withDecisionLog(ctx context.Context) (context.Context, *decisionLog) {
+	log := &decisionLog{}
+	return context.WithValue(ctx, decisionLogKey{}, log), log
+}
+
+// seededSource derives selection seeds from the injected source. Replays
+// are only reproducible when that source can be reseeded.
+type seededSource struct {
+	rnd domain.RandomSource
+}
+
+func (s seededSource) NextSeed() int64 {
+	return int64(s.rnd.Intn(math.MaxInt))
+}
+
+func (s seededSource) Deterministic() bool {
+	_, ok := s.rnd.(domain.SeedableSource)
+	return ok
+}
+
+type randomSelector struct {
+	seededSource
+}
+
+func NewRandomSelector(rnd domain.RandomSource) ReviewerSelector {
+	return &randomSelector{seededSource{rnd: rnd}}
+}
+
+func (s *randomSelector) Select(ctx context.Context, req SelectionRequest) ([]string, error) {
+	return randomSubset(s.rnd, candidateIDs(req.Candidates), req.Max), nil
+}
+
+func (s *randomSelector) SelectSeeded(ctx context.Context, req SelectionRequest, seed int64) ([]string, map[string]int, error) {
+	return s.Replay(req, nil, seed), nil, nil
+}
+
+func (s *randomSelector) Replay(req SelectionRequest, scores map[string]int, seed int64) []string {
+	return randomSubset(domain.Reseed(s.rnd, seed), candidateIDs(req.Candidates), req.Max)
+}
+
+type roundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func NewRoundRobinSelector() ReviewerSelector {
+	return &roundRobinSelector{last: map[string]string{}}
+}
+
+func (s *roundRobinSelector) NextSeed() int64 { return 0 }
+
+func (s *roundRobinSelector) Deterministic() bool { return true }
+
+func (s *roundRobinSelector) Select(ctx context.Context, req SelectionRequest) ([]string, error) {
+	out, _, err := s.SelectSeeded(ctx, req, 0)
+	return out, err
+}
+
+func (s *roundRobinSelector) SelectSeeded(ctx context.Context, req SelectionRequest, seed int64) ([]string, map[string]int, error) {
+	ids := candidateIDs(req.Candidates)
+	n := len(ids)
+	if n == 0 || req.Max <= 0 {
+		return nil, nil, nil
+	}
+	sort.Strings(ids)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := sort.SearchStrings(ids, s.last[req.TeamName])
+	if start < n && ids[start] == s.last[req.TeamName] {
+		start++
+	}
+
+	distance := make(map[string]int, n)
+	for i, id := range ids {
+		distance[id] = (i - start%n + n) % n
+	}
+
+	out := s.Replay(req, distance, seed)
+	s.last[req.TeamName] = out[len(out)-1]
+	return out, distance, nil
+}
+
+func (s *roundRobinSelector) Replay(req SelectionRequest, scores map[string]int, seed int64) []string {
+	ids := candidateIDs(req.Candidates)
+	if len(ids) == 0 || req.Max <= 0 {
+		return nil
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] < scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > req.Max {
+		ids = ids[:req.Max]
+	}
+	return ids
+}
+
+type weightedSelector struct {
+	seededSource
+}
+
+func NewWeightedSelector(rnd domain.RandomSource) ReviewerSelector {
+	return &weightedSelector{seededSource{rnd: rnd}}
+}
+
+func (s *weightedSelector) Select(ctx context.Context, req SelectionRequest) ([]string, error) {
+	return pickWeighted(s.rnd, candidateIDs(req.Candidates), candidateWeights(req.Candidates), req.Max), nil
+}
+
+func (s *weightedSelector) SelectSeeded(ctx context.Context, req SelectionRequest, seed int64) ([]string, map[string]int, error) {
+	weights := candidateWeights(req.Candidates)
+	return s.Replay(req, weights, seed), weights, nil
+}
+
+func (s *weightedSelector) Replay(req SelectionRequest, scores map[string]int, seed int64) []string {
+	return pickWeighted(domain.Reseed(s.rnd, seed), candidateIDs(req.Candidates), scores, req.Max)
+}
+
+type leastLoadedSelector struct {
+	seededSource
+	stats stats.Repository
+}
+
+func NewLeastLoadedSelector(statsRepo stats.Repository, rnd domain.RandomSource) ReviewerSelector {
+	return &leastLoadedSelector{seededSource: seededSource{rnd: rnd}, stats: statsRepo}
+}
+
+func (s *leastLoadedSelector) Select(ctx context.Context, req SelectionRequest) ([]string, error) {
+	open, err := s.openReviews(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return lowestScored(s.rnd, candidateIDs(req.Candidates), open, req.Max), nil
+}
+
+func (s *leastLoadedSelector) SelectSeeded(ctx context.Context, req SelectionRequest, seed int64) ([]string, map[string]int, error) {
+	open, err := s.openReviews(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.Replay(req, open, seed), open, nil
+}
+
+func (s *leastLoadedSelector) Replay(req SelectionRequest, scores map[string]int, seed int64) []string {
+	return lowestScored(domain.Reseed(s.rnd, seed), candidateIDs(req.Candidates), scores, req.Max)
+}
+
+func (s *leastLoadedSelector) openReviews(ctx context.Context, req SelectionRequest) (map[string]int, error) {
+	if len(req.Candidates) == 0 || req.Max <= 0 {
+		return nil, nil
+	}
+
+	teamName := req.TeamName
+	userStats, err := s.stats.GetUserAssignmentStats(ctx, &teamName)
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]int, len(req.Candidates))
+	for _, st := range userStats {
+		if _, ok := findUser(req.Candidates, st.UserID); ok {
+			open[st.UserID] = st.AssignedOpen
+		}
+	}
+	return open, nil
+}
+
+type rotationSelector struct {
+	seededSource
+	prs    Repository
+	teams  team.Repository
+	window int
+}
+
+func NewRotationSelector(prs Repository, teams team.Repository, rnd domain.RandomSource, window int) ReviewerSelector {
+	if window <= 0 {
+		window = DefaultRotationWindow
+	}
+	return &rotationSelector{seededSource: seededSource{rnd: rnd}, prs: prs, teams: teams, window: window}
+}
+
+func (s *rotationSelector) Select(ctx context.Context, req SelectionRequest) ([]string, error) {
+	recency, err := s.recency(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return lowestScored(s.rnd, candidateIDs(req.Candidates), recency, req.Max), nil
+}
+
+func (s *rotationSelector) SelectSeeded(ctx context.Context, req SelectionRequest, seed int64) ([]string, map[string]int, error) {
+	recency, err := s.recency(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.Replay(req, recency, seed), recency, nil
+}
+
+func (s *rotationSelector) Replay(req SelectionRequest, scores map[string]int, seed int64) []string {
+	return lowestScored(domain.Reseed(s.rnd, seed), candidateIDs(req.Candidates), scores, req.Max)
+}
+
+func (s *rotationSelector) recency(ctx context.Context, req SelectionRequest) (map[string]int, error) {
+	if len(req.Candidates) == 0 || req.Max <= 0 {
+		return nil, nil
+	}
+
+	window := s.window
+	if req.TeamName != "" {
+		settings, err := s.teams.GetSettings(ctx, req.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		if settings.RotationWindow > 0 {
+			window = settings.RotationWindow
+		}
+	}
+
+	history, err := s.prs.RecentReviewers(ctx, req.AuthorID, window)
+	if err != nil {
+		return nil, err
+	}
+	recency := map[string]int{}
+	for i, reviewers := range history {
+		for _, id := range reviewers {
+			if _, ok := findUser(req.Candidates, id); ok {
+				recency[id] += window - i
+			}
+		}
+	}
+	return recency, nil
+}
+
+func pickWeighted(rnd domain.RandomSource, ids []string, weights map[string]int, max int) []string {
+	if len(ids) == 0 || max <= 0 {
+		return nil
+	}
+
+	pool := append([]string(nil), ids...)
+	out := make([]string, 0, min(max, len(pool)))
+	for len(pool) > 0 && len(out) < max {
+		total := 0
+		for _, id := range pool {
+			total += positiveWeight(weights[id])
+		}
+
+		r := rnd.Intn(total)
+		idx := 0
+		for i, id := range pool {
+			r -= positiveWeight(weights[id])
+			if r < 0 {
+				idx = i
+				break
+			}
+		}
+
+		out = append(out, pool[idx])
+		pool = append(pool[:idx], pool[idx+1:]...)
+	}
+	return out
+}
+
+func lowestScored(rnd domain.RandomSource, ids []string, scores map[string]int, max int) []string {
+	if len(ids) == 0 || max <= 0 {
+		return nil
+	}
+	ids = randomSubset(rnd, ids, len(ids))
+	sort.SliceStable(ids, func(i, j int) bool { return scores[ids[i]] < scores[ids[j]] })
+
+	if len(ids) > max {
+		ids = ids[:max]
+	}
+	return ids
+}
+
+func candidateWeights(users []user.User) map[string]int {
+	weights := make(map[string]int, len(users))
+	for _, u := range users {
+		weights[u.ID] = weightOf(u)
+	}
+	return weights
+}
+
+func weightOf(u user.User) int {
+	return positiveWeight(u.ReviewWeight)
+}
+
+func positiveWeight(w int) int {
+	if w <= 0 {
+		return 1
+	}
+	return w
+}
+
+func candidateIDs(users []user.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func randomSubset(r domain.RandomSource, items []string, max int) []string {
+	n := len(items)
+	if n == 0 || max <= 0 {
+		return nil
+	}
+	out := make([]string, n)
+	copy(out, items)
+	r.Shuffle(n, func(i, j int) { out[i], out[j] = out[j], out[i] })
+	if n > max {
+		out = out[:max]
+	}
+	return out
+}
@@ -2,93 +2,116 @@ package pr
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"prservice/internal/domain"
+	"prservice/internal/domain/team"
 	"prservice/internal/domain/user"
 )
 
 type Service interface {
-	Create(ctx context.Context, id, name, authorID string) (PullRequest, error)
-	Merge(ctx context.Context, id string) (PullRequest, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (PullRequest, string, error)
+	Create(ctx context.Context, params CreateParams) (PullRequest, error)
+	Merge(ctx context.Context, id string, force bool) (PullRequest, error)
+	MarkReady(ctx context.Context, id string) (PullRequest, error)
+	Close(ctx context.Context, id string) (PullRequest, error)
+	Reopen(ctx context.Context, id string) (PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string) (PullRequest, string, error)
+	DeclineReview(ctx context.Context, prID, userID, reason string) (PullRequest, string, error)
+	AddReviewer(ctx context.Context, prID, userID string) (PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID, userID string) (PullRequest, error)
 	GetUserReviews(ctx context.Context, userID string) ([]PullRequestShort, error)
+	SubmitReview(ctx context.Context, prID, userID string, action ReviewAction, comment string) (PullRequest, error)
+	DismissReview(ctx context.Context, prID, userID, comment string) (PullRequest, error)
+	ReassignOpenReviews(ctx context.Context, userID string) (user.ReassignReport, error)
+	RedistributeReviews(ctx context.Context, teamName string, userIDs []string) (user.ReassignReport, error)
+	ExplainAssignment(ctx context.Context, prID string) ([]Assignment, error)
 }
 
 type service struct {
-	uow    domain.UnitOfWork
-	prs    Repository
-	users  user.Repository
-	events domain.EventBus
-	rnd    domain.RandomSource
+	uow       domain.UnitOfWork
+	prs       Repository
+	users     user.Repository
+	teams     team.Repository
+	events    domain.EventPublisher
+	selectors Selectors
 }
 
 func NewService(
 	uow domain.UnitOfWork,
 	prs Repository,
 	users user.Repository,
-	events domain.EventBus,
-	rnd domain.RandomSource,
+	teams team.Repository,
+	events domain.EventPublisher,
+	selectors Selectors,
 ) Service {
 	return &service{
-		uow:    uow,
-		prs:    prs,
-		users:  users,
-		events: events,
-		rnd:    rnd,
+		uow:       uow,
+		prs:       prs,
+		users:     users,
+		teams:     teams,
+		events:    events,
+		selectors: selectors,
 	}
 }
 
-func (s *service) Create(ctx context.Context, id, name, authorID string) (PullRequest, error) {
+func (s *service) Create(ctx context.Context, params CreateParams) (PullRequest, error) {
 	var res PullRequest
 
 	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
-		author, err := s.users.GetByID(ctx, authorID)
+		ctx, log := withDecisionLog(ctx)
+
+		author, err := s.getAuthor(ctx, params.AuthorID)
 		if err != nil {
 			return err
 		}
-		if author.TeamName == "" {
-			return &domain.DomainError{
-				Code:       domain.ErrorCodeNotFound,
-				Message:    "author has no team",
-				HTTPStatus: http.StatusNotFound,
+
+		pr := PullRequest{
+			ID:       params.ID,
+			Name:     params.Name,
+			AuthorID: author.ID,
+			Status:   StatusOpen,
+			Files:    normalizeList(params.Files),
+			Labels:   normalizeList(params.Labels),
+		}
+		if params.Draft {
+			pr.Status = StatusDraft
+		} else {
+			pr.AssignedReviewers, pr.Staffing, err = s.selectReviewers(ctx, author, pr, nil)
+			if err != nil {
+				return err
 			}
 		}
 
-		candidates, err := s.users.GetActiveTeamMembersExcept(ctx, author.TeamName, author.ID)
+		created, err := s.prs.CreateWithReviewers(ctx, pr)
 		if err != nil {
 			return err
 		}
-
-		ids := make([]string, 0, len(candidates))
-		for _, u := range candidates {
-			ids = append(ids, u.ID)
-		}
-
-		selected := randomSubset(s.rnd, ids, 2)
-
-		pr := PullRequest{
-			ID:                id,
-			Name:              name,
-			AuthorID:          author.ID,
-			Status:            StatusOpen,
-			AssignedReviewers: selected,
+		if err := s.recordAssignment(ctx, created.ID, "create", log); err != nil {
+			return err
 		}
-
-		created, err := s.prs.CreateWithReviewers(ctx, pr)
+		created.Staffing = pr.Staffing
+		created.Reviews, err = s.prs.GetReviews(ctx, created.ID)
 		if err != nil {
 			return err
 		}
 		res = created
 
 		if s.events != nil {
-			s.events.Publish(ctx, domain.Event{
-				Type: "pr.created",
-				Payload: map[string]any{
-					"pr_id":     res.ID,
-					"author_id": res.AuthorID,
-				},
-			})
+			if err := s.events.Publish(ctx, domain.PRCreated{
+				PullRequestID:   res.ID,
+				PullRequestName: res.Name,
+				AuthorID:        res.AuthorID,
+				TeamName:        author.TeamName,
+				Status:          string(res.Status),
+				Reviewers:       append([]string{}, res.AssignedReviewers...),
+			}); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -97,7 +120,7 @@ func (s *service) Create(ctx context.Context, id, name, authorID string) (PullRe
 	return res, err
 }
 
-func (s *service) Merge(ctx context.Context, id string) (PullRequest, error) {
+func (s *service) Merge(ctx context.Context, id string, force bool) (PullRequest, error) {
 	var res PullRequest
 
 	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
@@ -107,31 +130,45 @@ func (s *service) Merge(ctx context.Context, id string) (PullRequest, error) {
 		}
 
 		if current.Status == StatusMerged {
-			revs, err := s.prs.GetReviewers(ctx, id)
-			if err != nil {
+			if err := s.loadReviews(ctx, &current); err != nil {
 				return err
 			}
-			current.AssignedReviewers = revs
 			res = current
 			return nil
 		}
+		if !current.Status.CanTransitionTo(StatusMerged) {
+			return invalidTransition(current.Status, StatusMerged)
+		}
+
+		if !force {
+			if err := s.checkApprovals(ctx, current); err != nil {
+				return err
+			}
+		}
 
-		updated, err := s.prs.UpdateStatusMerged(ctx, id)
+		updated, err := s.prs.UpdateStatusMerged(ctx, id, force)
 		if err != nil {
 			return err
 		}
-		revs, err := s.prs.GetReviewers(ctx, id)
-		if err != nil {
+		if err := s.loadReviews(ctx, &updated); err != nil {
 			return err
 		}
-		updated.AssignedReviewers = revs
 		res = updated
 
 		if s.events != nil {
-			s.events.Publish(ctx, domain.Event{
-				Type:    "pr.merged",
-				Payload: map[string]any{"pr_id": id},
-			})
+			author, err := s.getAuthor(ctx, res.AuthorID)
+			if err != nil {
+				return err
+			}
+			if err := s.events.Publish(ctx, domain.PRMerged{
+				PullRequestID: id,
+				AuthorID:      res.AuthorID,
+				TeamName:      author.TeamName,
+				Reviewers:     append([]string{}, res.AssignedReviewers...),
+				Forced:        force,
+			}); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -140,127 +177,1494 @@ func (s *service) Merge(ctx context.Context, id string) (PullRequest, error) {
 	return res, err
 }
 
-func (s *service) ReassignReviewer(ctx context.Context, prID, oldUserID string) (PullRequest, string, error) {
+func (s *service) MarkReady(ctx context.Context, id string) (PullRequest, error) {
+	return s.transition(ctx, id, StatusDraft, StatusOpen, func(t domain.PRTransition) domain.Event {
+		return domain.PRMarkedReady{PRTransition: t}
+	})
+}
+
+func (s *service) Close(ctx context.Context, id string) (PullRequest, error) {
+	return s.transition(ctx, id, "", StatusClosed, func(t domain.PRTransition) domain.Event {
+		return domain.PRClosed{PRTransition: t}
+	})
+}
+
+func (s *service) Reopen(ctx context.Context, id string) (PullRequest, error) {
+	return s.transition(ctx, id, StatusClosed, StatusOpen, func(t domain.PRTransition) domain.Event {
+		return domain.PRReopened{PRTransition: t}
+	})
+}
+
+func (s *service) transition(
+	ctx context.Context,
+	id string,
+	from, to Status,
+	event func(domain.PRTransition) domain.Event,
+) (PullRequest, error) {
 	var res PullRequest
-	var replacedBy string
 
 	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
-		current, err := s.prs.LockByID(ctx, prID)
+		current, err := s.prs.LockByID(ctx, id)
 		if err != nil {
 			return err
 		}
-		if current.Status == StatusMerged {
-			return &domain.DomainError{
-				Code:       domain.ErrorCodePRMerged,
-				Message:    "cannot reassign on merged PR",
-				HTTPStatus: http.StatusConflict,
+
+		if current.Status == to {
+			if err := s.loadReviews(ctx, &current); err != nil {
+				return err
+			}
+			res = current
+			return nil
+		}
+		if (from != "" && current.Status != from) || !current.Status.CanTransitionTo(to) {
+			return invalidTransition(current.Status, to)
+		}
+
+		var staffing *Staffing
+		if to == StatusOpen {
+			fillCtx, log := withDecisionLog(ctx)
+			staffing, err = s.fillReviewers(fillCtx, current)
+			if err != nil {
+				return err
+			}
+			operation := "reopen"
+			if current.Status == StatusDraft {
+				operation = "mark_ready"
+			}
+			if err := s.recordAssignment(ctx, id, operation, log); err != nil {
+				return err
 			}
 		}
 
-		oldUser, err := s.users.GetByID(ctx, oldUserID)
+		updated, err := s.prs.UpdateStatus(ctx, id, to)
 		if err != nil {
 			return err
 		}
-		if oldUser.TeamName == "" {
-			return &domain.DomainError{
-				Code:       domain.ErrorCodeNotFound,
-				Message:    "user has no team",
-				HTTPStatus: http.StatusNotFound,
+		if err := s.loadReviews(ctx, &updated); err != nil {
+			return err
+		}
+		updated.Staffing = staffing
+		res = updated
+
+		if s.events != nil {
+			author, err := s.getAuthor(ctx, res.AuthorID)
+			if err != nil {
+				return err
+			}
+			if err := s.events.Publish(ctx, event(domain.PRTransition{
+				PullRequestID: id,
+				AuthorID:      res.AuthorID,
+				TeamName:      author.TeamName,
+				Reviewers:     append([]string{}, res.AssignedReviewers...),
+				From:          string(current.Status),
+				To:            string(to),
+			})); err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+
+	return res, err
+}
+
+func (s *service) fillReviewers(ctx context.Context, p PullRequest) (*Staffing, error) {
+	author, err := s.getAuthor(ctx, p.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := s.prs.GetReviewers(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, staffing, err := s.selectReviewers(ctx, author, p, assigned)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return staffing, nil
+	}
+	return staffing, s.prs.SetReviewers(ctx, p.ID, append(assigned, selected...))
+}
+
+func (s *service) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string) (PullRequest, string, error) {
+	var res PullRequest
+	var replacedBy string
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		current, oldUser, err := s.lockForReassign(ctx, prID, oldUserID)
+		if err != nil {
+			return err
+		}
 
-		assigned, err := s.prs.UserIsReviewer(ctx, prID, oldUserID)
+		replaceCtx, log := withDecisionLog(ctx)
+		res, replacedBy, err = s.replaceReviewer(replaceCtx, current, oldUser, newUserID)
 		if err != nil {
 			return err
 		}
-		if !assigned {
-			return &domain.DomainError{
-				Code:       domain.ErrorCodeNotAssigned,
-				Message:    "reviewer is not assigned to this PR",
-				HTTPStatus: http.StatusConflict,
-			}
+		return s.recordAssignment(ctx, prID, "reassign", log)
+	})
+
+	return res, replacedBy, err
+}
+
+func (s *service) DeclineReview(ctx context.Context, prID, userID, reason string) (PullRequest, string, error) {
+	if strings.TrimSpace(reason) == "" {
+		return PullRequest{}, "", &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "decline reason is required",
+			HTTPStatus: http.StatusBadRequest,
 		}
+	}
 
-		currentReviewers, err := s.prs.GetReviewers(ctx, prID)
+	var res PullRequest
+	var replacedBy string
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		current, reviewer, err := s.lockForReassign(ctx, prID, userID)
 		if err != nil {
 			return err
 		}
 
-		candidatesUsers, err := s.users.GetActiveTeamMembersExcept(ctx, oldUser.TeamName, oldUser.ID)
+		replaceCtx, log := withDecisionLog(ctx)
+		res, replacedBy, err = s.replaceReviewer(replaceCtx, current, reviewer, "")
+		var noCandidate *domain.DomainError
+		if errors.As(err, &noCandidate) && noCandidate.Code == domain.ErrorCodeNoCandidate {
+			res, err = s.dropReviewer(ctx, current, reviewer.ID, noCandidate.Message)
+		}
 		if err != nil {
 			return err
 		}
+		if err := s.recordAssignment(ctx, prID, "decline", log); err != nil {
+			return err
+		}
 
-		filteredIDs := make([]string, 0, len(candidatesUsers))
-		for _, u := range candidatesUsers {
-			if u.ID == current.AuthorID {
-				continue
+		if err := s.prs.RecordDecline(ctx, Decline{
+			PRID:       prID,
+			UserID:     userID,
+			ReplacedBy: replacedBy,
+			Reason:     reason,
+		}); err != nil {
+			return err
+		}
+
+		if s.events != nil {
+			author, err := s.getAuthor(ctx, res.AuthorID)
+			if err != nil {
+				return err
 			}
-			if contains(currentReviewers, u.ID) {
-				continue
+			if err := s.events.Publish(ctx, domain.ReviewDeclined{
+				PullRequestID: prID,
+				AuthorID:      res.AuthorID,
+				TeamName:      author.TeamName,
+				ReviewerID:    userID,
+				NewReviewerID: replacedBy,
+				Reason:        reason,
+			}); err != nil {
+				return err
 			}
-			filteredIDs = append(filteredIDs, u.ID)
 		}
-		if len(filteredIDs) == 0 {
-			return &domain.DomainError{
-				Code:       domain.ErrorCodeNoCandidate,
-				Message:    "no active replacement candidate in team",
-				HTTPStatus: http.StatusConflict,
+		return nil
+	})
+
+	return res, replacedBy, err
+}
+
+// dropReviewer removes a reviewer who declined when nobody can take over,
+// reporting the PR as under-staffed instead of failing the decline.
+func (s *service) dropReviewer(ctx context.Context, current PullRequest, userID, reason string) (PullRequest, error) {
+	reviewers, err := s.prs.GetReviewers(ctx, current.ID)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	rest := make([]string, 0, len(reviewers))
+	for _, rID := range reviewers {
+		if rID != userID {
+			rest = append(rest, rID)
+		}
+	}
+	if err := s.prs.SetReviewers(ctx, current.ID, rest); err != nil {
+		return PullRequest{}, err
+	}
+	if err := s.loadReviews(ctx, &current); err != nil {
+		return PullRequest{}, err
+	}
+
+	author, err := s.getAuthor(ctx, current.AuthorID)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	settings, err := s.teams.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	if len(rest) < settings.MaxReviewers {
+		current.Staffing = &Staffing{
+			Requested: settings.MaxReviewers,
+			Assigned:  len(rest),
+			Reason:    reason,
+		}
+	}
+	return current, nil
+}
+
+func (s *service) lockForReassign(ctx context.Context, prID, userID string) (PullRequest, user.User, error) {
+	current, err := s.prs.LockByID(ctx, prID)
+	if err != nil {
+		return PullRequest{}, user.User{}, err
+	}
+	if current.Status == StatusMerged {
+		return PullRequest{}, user.User{}, &domain.DomainError{
+			Code:       domain.ErrorCodePRMerged,
+			Message:    "cannot reassign on merged PR",
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+	if current.Status != StatusOpen {
+		return PullRequest{}, user.User{}, &domain.DomainError{
+			Code:       domain.ErrorCodePRNotOpen,
+			Message:    "cannot reassign on " + string(current.Status) + " PR",
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return PullRequest{}, user.User{}, err
+	}
+	if u.TeamName == "" {
+		return PullRequest{}, user.User{}, &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "user has no team",
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+
+	assigned, err := s.prs.UserIsReviewer(ctx, prID, userID)
+	if err != nil {
+		return PullRequest{}, user.User{}, err
+	}
+	if !assigned {
+		return PullRequest{}, user.User{}, &domain.DomainError{
+			Code:       domain.ErrorCodeNotAssigned,
+			Message:    "reviewer is not assigned to this PR",
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+	return current, u, nil
+}
+
+func (s *service) ReassignOpenReviews(ctx context.Context, userID string) (user.ReassignReport, error) {
+	var report user.ReassignReport
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		u, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		report, err = s.redistribute(ctx, u.TeamName, []string{u.ID})
+		return err
+	})
+
+	return report, err
+}
+
+func (s *service) RedistributeReviews(ctx context.Context, teamName string, userIDs []string) (user.ReassignReport, error) {
+	var report user.ReassignReport
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		report, err = s.redistribute(ctx, teamName, userIDs)
+		return err
+	})
+
+	return report, err
+}
+
+func (s *service) redistribute(ctx context.Context, teamName string, userIDs []string) (user.ReassignReport, error) {
+	var report user.ReassignReport
+
+	settings, err := s.teams.GetSettings(ctx, teamName)
+	if err != nil {
+		return report, err
+	}
+	members, err := s.users.GetActiveTeamMembersExcept(ctx, teamName, "")
+	if err != nil {
+		return report, err
+	}
+
+	counts, err := s.openReviewCounts(ctx, members)
+	if err != nil {
+		return report, err
+	}
+
+	leaving := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		leaving[id] = true
+	}
+
+	levels := map[string]user.Seniority{}
+	if settings.MentorPairing {
+		for _, id := range userIDs {
+			u, err := s.users.GetByID(ctx, id)
+			if err != nil {
+				return report, err
 			}
+			levels[id] = u.Seniority
 		}
+	}
+
+	affected, err := s.prs.LockOpenByReviewers(ctx, userIDs)
+	if err != nil {
+		return report, err
+	}
 
-		selected := randomSubset(s.rnd, filteredIDs, 1)
-		replacedBy = selected[0]
+	selector := s.selectors.For(Strategy(settings.ReviewerStrategy))
+	load := map[string]int{}
+	exclusions := map[string][]string{}
+	preferences := map[string][]string{}
+	authorTeams := map[string]string{}
+	rules := map[string][]team.PairRule{}
+	fallbacks := map[string][]string{}
+	partners := map[string][]user.User{}
+	var changes []ReviewerChange
+	var assignments []Assignment
+	var events []domain.Event
 
-		newReviewers := make([]string, 0, len(currentReviewers))
-		for _, rID := range currentReviewers {
-			if rID == oldUserID {
-				newReviewers = append(newReviewers, replacedBy)
-			} else {
-				newReviewers = append(newReviewers, rID)
+	for _, current := range affected {
+		prID := current.ID
+		reviewers := current.AssignedReviewers
+
+		next := make([]string, 0, len(reviewers))
+		for _, rID := range reviewers {
+			if !leaving[rID] {
+				next = append(next, rID)
 			}
 		}
+		prCtx, log := withDecisionLog(ctx)
 
-		if err := s.prs.SetReviewers(ctx, prID, newReviewers); err != nil {
-			return err
+		excluded, ok := exclusions[current.AuthorID]
+		if !ok {
+			author, found := findUser(members, current.AuthorID)
+			if !found {
+				if author, err = s.users.GetByID(ctx, current.AuthorID); err != nil {
+					return report, err
+				}
+			}
+			teamRules, ok := rules[author.TeamName]
+			if !ok {
+				if teamRules, err = s.teams.ListPairRules(ctx, author.TeamName); err != nil {
+					return report, err
+				}
+				rules[author.TeamName] = teamRules
+			}
+			excluded, preferences[current.AuthorID] = pairRulesFor(author.ID, teamRules)
+			exclusions[current.AuthorID] = excluded
+			authorTeams[current.AuthorID] = author.TeamName
 		}
 
-		current.AssignedReviewers = newReviewers
-		res = current
+		for _, rID := range reviewers {
+			if !leaving[rID] {
+				continue
+			}
 
-		if s.events != nil {
-			s.events.Publish(ctx, domain.Event{
-				Type: "pr.reassign",
-				Payload: map[string]any{
-					"pr_id":       prID,
-					"old_user":    oldUserID,
-					"replaced_by": replacedBy,
-				},
+			eligible := func(pool []user.User) []user.User {
+				var candidates []user.User
+				for _, u := range pool {
+					if leaving[u.ID] || u.ID == current.AuthorID || contains(next, u.ID) || contains(reviewers, u.ID) || contains(excluded, u.ID) {
+						continue
+					}
+					if atReviewCapacity(u, counts) {
+						continue
+					}
+					candidates = append(candidates, u)
+				}
+				if pool := onlyIn(candidates, preferences[current.AuthorID]); len(pool) > 0 {
+					return pool
+				}
+				return candidates
+			}
+
+			selected, err := selector.Select(prCtx, SelectionRequest{
+				TeamName:   teamName,
+				AuthorID:   current.AuthorID,
+				Candidates: leastLoaded(sameSeniorityFirst(eligible(members), levels[rID]), load),
+				Max:        1,
+				Purpose:    "redistribution",
+			})
+			if err != nil {
+				return report, err
+			}
+			if len(selected) == 0 {
+				authorTeam := authorTeams[current.AuthorID]
+				fallback, ok := fallbacks[authorTeam]
+				if !ok {
+					if fallback, err = s.fallbackTeams(ctx, authorTeam, teamName); err != nil {
+						return report, err
+					}
+					fallbacks[authorTeam] = fallback
+				}
+				for _, partner := range fallback {
+					pool, ok := partners[partner]
+					if !ok {
+						if pool, err = s.users.GetActiveTeamMembersExcept(ctx, partner, ""); err != nil {
+							return report, err
+						}
+						partnerCounts, err := s.openReviewCounts(ctx, pool)
+						if err != nil {
+							return report, err
+						}
+						for id, n := range partnerCounts {
+							if _, ok := counts[id]; !ok {
+								counts[id] = n
+							}
+						}
+						partners[partner] = pool
+					}
+					selected, err = selector.Select(prCtx, SelectionRequest{
+						TeamName:   partner,
+						AuthorID:   current.AuthorID,
+						Candidates: leastLoaded(sameSeniorityFirst(eligible(pool), levels[rID]), load),
+						Max:        1,
+						Purpose:    "partner_team",
+					})
+					if err != nil {
+						return report, err
+					}
+					if len(selected) > 0 {
+						break
+					}
+				}
+			}
+			if len(selected) == 0 {
+				changes = append(changes, ReviewerChange{PRID: prID, OldUserID: rID})
+				report.WithoutCandidate = append(report.WithoutCandidate, user.Reassignment{PRID: prID, UserID: rID})
+				events = append(events, domain.ReviewerUnassigned{
+					PullRequestID: prID,
+					AuthorID:      current.AuthorID,
+					TeamName:      authorTeams[current.AuthorID],
+					ReviewerID:    rID,
+				})
+				continue
+			}
+
+			replacedBy := selected[0]
+			changes = append(changes, ReviewerChange{PRID: prID, OldUserID: rID, NewUserID: replacedBy})
+			next = append(next, replacedBy)
+			load[replacedBy]++
+			counts[replacedBy]++
+			report.Reassigned = append(report.Reassigned, user.Reassignment{PRID: prID, UserID: rID, ReplacedBy: replacedBy})
+
+			events = append(events, domain.ReviewerReassigned{
+				PullRequestID: prID,
+				AuthorID:      current.AuthorID,
+				TeamName:      authorTeams[current.AuthorID],
+				OldReviewerID: rID,
+				NewReviewerID: replacedBy,
+				Redistributed: true,
 			})
 		}
 
-		return nil
-	})
+		if len(log.decisions) > 0 {
+			assignments = append(assignments, Assignment{PRID: prID, Operation: "redistribute", Decisions: log.decisions})
+		}
+	}
 
-	return res, replacedBy, err
+	if err := s.prs.ReplaceReviewers(ctx, changes); err != nil {
+		return report, err
+	}
+	if err := s.prs.RecordAssignments(ctx, assignments); err != nil {
+		return report, err
+	}
+	if s.events != nil {
+		if err := domain.PublishAll(ctx, s.events, events); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
 }
 
-func (s *service) GetUserReviews(ctx context.Context, userID string) ([]PullRequestShort, error) {
-	return s.prs.GetUserPRs(ctx, userID)
-}
+func (s *service) replaceReviewer(ctx context.Context, current PullRequest, oldUser user.User, newUserID string) (PullRequest, string, error) {
+	currentReviewers, err := s.prs.GetReviewers(ctx, current.ID)
+	if err != nil {
+		return PullRequest{}, "", err
+	}
 
-func randomSubset(r domain.RandomSource, items []string, max int) []string {
-	n := len(items)
-	if n == 0 || max <= 0 {
-		return nil
+	author, err := s.getAuthor(ctx, current.AuthorID)
+	if err != nil {
+		return PullRequest{}, "", err
+	}
+	excluded, preferred, err := s.pairRules(ctx, author)
+	if err != nil {
+		return PullRequest{}, "", err
+	}
+
+	var declined []string
+	if newUserID == "" {
+		declined, err = s.prs.GetDeclinedUsers(ctx, current.ID)
+		if err != nil {
+			return PullRequest{}, "", err
+		}
+	}
+
+	settings, err := s.teams.GetSettings(ctx, oldUser.TeamName)
+	if err != nil {
+		return PullRequest{}, "", err
+	}
+	selector := s.selectors.For(Strategy(settings.ReviewerStrategy))
+
+	var atCapacity []string
+	pick := func(teamName, purpose string) (string, error) {
+		members, err := s.users.GetActiveTeamMembersExcept(ctx, teamName, oldUser.ID)
+		if err != nil {
+			return "", err
+		}
+		filtered := make([]user.User, 0, len(members))
+		for _, u := range members {
+			if u.ID == current.AuthorID {
+				continue
+			}
+			if contains(currentReviewers, u.ID) || contains(declined, u.ID) || contains(excluded, u.ID) {
+				continue
+			}
+			filtered = append(filtered, u)
+		}
+
+		counts, err := s.openReviewCounts(ctx, filtered)
+		if err != nil {
+			return "", err
+		}
+		available, full := splitByCapacity(filtered, counts)
+		atCapacity = append(atCapacity, full...)
+
+		if newUserID != "" {
+			if _, ok := findUser(available, newUserID); ok {
+				return newUserID, nil
+			}
+			return "", nil
+		}
+		if len(available) == 0 {
+			return "", nil
+		}
+
+		if pool := onlyIn(available, preferred); len(pool) > 0 {
+			available = pool
+		}
+		if settings.MentorPairing {
+			available = sameSeniorityFirst(available, oldUser.Seniority)
+		}
+
+		selected, err := selector.Select(ctx, SelectionRequest{
+			TeamName:   teamName,
+			AuthorID:   current.AuthorID,
+			Candidates: available,
+			Max:        1,
+			Purpose:    purpose,
+		})
+		if err != nil || len(selected) == 0 {
+			return "", err
+		}
+		return selected[0], nil
+	}
+
+	replacedBy, err := pick(oldUser.TeamName, "replacement")
+	if err != nil {
+		return PullRequest{}, "", err
+	}
+	if replacedBy == "" {
+		fallback, err := s.fallbackTeams(ctx, author.TeamName, oldUser.TeamName)
+		if err != nil {
+			return PullRequest{}, "", err
+		}
+		for _, teamName := range fallback {
+			if replacedBy, err = pick(teamName, "partner_team"); err != nil {
+				return PullRequest{}, "", err
+			}
+			if replacedBy != "" {
+				break
+			}
+		}
 	}
-	out := make([]string, n)
-	copy(out, items)
-	r.Shuffle(n, func(i, j int) { out[i], out[j] = out[j], out[i] })
-	if n > max {
-		out = out[:max]
+
+	if replacedBy == "" {
+		switch {
+		case newUserID != "" && contains(atCapacity, newUserID):
+			return PullRequest{}, "", reviewCapacityReached(newUserID)
+		case newUserID != "":
+			return PullRequest{}, "", &domain.DomainError{
+				Code:       domain.ErrorCodeNoCandidate,
+				Message:    "user " + newUserID + " is not an eligible replacement",
+				HTTPStatus: http.StatusConflict,
+			}
+		case len(atCapacity) > 0:
+			return PullRequest{}, "", &domain.DomainError{
+				Code:       domain.ErrorCodeNoCandidate,
+				Message:    "all replacement candidates are at review capacity",
+				HTTPStatus: http.StatusConflict,
+			}
+		default:
+			return PullRequest{}, "", noReplacementCandidate()
+		}
+	}
+
+	newReviewers := make([]string, 0, len(currentReviewers))
+	for _, rID := range currentReviewers {
+		if rID == oldUser.ID {
+			newReviewers = append(newReviewers, replacedBy)
+		} else {
+			newReviewers = append(newReviewers, rID)
+		}
+	}
+
+	if err := s.prs.SetReviewers(ctx, current.ID, newReviewers); err != nil {
+		return PullRequest{}, "", err
+	}
+
+	current.AssignedReviewers = newReviewers
+	current.Reviews, err = s.prs.GetReviews(ctx, current.ID)
+	if err != nil {
+		return PullRequest{}, "", err
+	}
+
+	if s.events != nil {
+		if err := s.events.Publish(ctx, domain.ReviewerReassigned{
+			PullRequestID: current.ID,
+			AuthorID:      current.AuthorID,
+			TeamName:      author.TeamName,
+			OldReviewerID: oldUser.ID,
+			NewReviewerID: replacedBy,
+			Manual:        newUserID != "",
+		}); err != nil {
+			return PullRequest{}, "", err
+		}
+	}
+
+	return current, replacedBy, nil
+}
+
+func (s *service) AddReviewer(ctx context.Context, prID, userID string) (PullRequest, error) {
+	var res PullRequest
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.lockForReviewerChange(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		reviewers, err := s.prs.GetReviewers(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if contains(reviewers, userID) {
+			if err := s.loadReviews(ctx, &current); err != nil {
+				return err
+			}
+			res = current
+			return nil
+		}
+
+		author, err := s.getAuthor(ctx, current.AuthorID)
+		if err != nil {
+			return err
+		}
+		candidate, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		settings, err := s.teams.GetSettings(ctx, author.TeamName)
+		if err != nil {
+			return err
+		}
+		excluded, _, err := s.pairRules(ctx, author)
+		if err != nil {
+			return err
+		}
+		away, err := s.users.ListUnavailability(ctx, userID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		unavailable := slices.ContainsFunc(away, func(w user.Unavailability) bool { return w.Covers(now) })
+		inPool := candidate.TeamName == author.TeamName || contains(settings.PartnerTeams, candidate.TeamName)
+		if candidate.ID == author.ID || !candidate.IsActive || unavailable || !inPool || contains(excluded, candidate.ID) {
+			return &domain.DomainError{
+				Code:       domain.ErrorCodeNoCandidate,
+				Message:    "user " + userID + " cannot review this PR",
+				HTTPStatus: http.StatusConflict,
+			}
+		}
+
+		counts, err := s.openReviewCounts(ctx, []user.User{candidate})
+		if err != nil {
+			return err
+		}
+		if atReviewCapacity(candidate, counts) {
+			return reviewCapacityReached(userID)
+		}
+
+		if len(reviewers) >= settings.MaxReviewers {
+			return &domain.DomainError{
+				Code:       domain.ErrorCodeTooManyReviewers,
+				Message:    fmt.Sprintf("PR already has %d of %d reviewers", len(reviewers), settings.MaxReviewers),
+				HTTPStatus: http.StatusConflict,
+			}
+		}
+
+		if err := s.prs.SetReviewers(ctx, prID, append(reviewers, userID)); err != nil {
+			return err
+		}
+		if err := s.loadReviews(ctx, &current); err != nil {
+			return err
+		}
+		res = current
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.ReviewerAdded{
+				PullRequestID: prID,
+				AuthorID:      current.AuthorID,
+				TeamName:      author.TeamName,
+				ReviewerID:    userID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+func (s *service) RemoveReviewer(ctx context.Context, prID, userID string) (PullRequest, error) {
+	var res PullRequest
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.lockForReviewerChange(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		reviewers, err := s.prs.GetReviewers(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if !contains(reviewers, userID) {
+			return &domain.DomainError{
+				Code:       domain.ErrorCodeNotAssigned,
+				Message:    "reviewer is not assigned to this PR",
+				HTTPStatus: http.StatusConflict,
+			}
+		}
+
+		rest := make([]string, 0, len(reviewers)-1)
+		for _, rID := range reviewers {
+			if rID != userID {
+				rest = append(rest, rID)
+			}
+		}
+		if err := s.prs.SetReviewers(ctx, prID, rest); err != nil {
+			return err
+		}
+		if err := s.loadReviews(ctx, &current); err != nil {
+			return err
+		}
+		res = current
+
+		if s.events != nil {
+			author, err := s.getAuthor(ctx, current.AuthorID)
+			if err != nil {
+				return err
+			}
+			if err := s.events.Publish(ctx, domain.ReviewerRemoved{
+				PullRequestID: prID,
+				AuthorID:      current.AuthorID,
+				TeamName:      author.TeamName,
+				ReviewerID:    userID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+func (s *service) GetUserReviews(ctx context.Context, userID string) ([]PullRequestShort, error) {
+	return s.prs.GetUserPRs(ctx, userID)
+}
+
+func (s *service) SubmitReview(ctx context.Context, prID, userID string, action ReviewAction, comment string) (PullRequest, error) {
+	if !action.Valid() {
+		return PullRequest{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "invalid review action",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	var res PullRequest
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		current, review, err := s.lockForReview(ctx, prID, userID)
+		if err != nil {
+			return err
+		}
+
+		state := review.State
+		switch action {
+		case ReviewActionApprove:
+			state = ReviewStateApproved
+		case ReviewActionRequestChanges:
+			state = ReviewStateChangesRequested
+		}
+
+		if err := s.prs.SetReviewState(ctx, prID, userID, state, comment); err != nil {
+			return err
+		}
+		if err := s.loadReviews(ctx, &current); err != nil {
+			return err
+		}
+		res = current
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.PRReviewed{
+				PullRequestID: prID,
+				ReviewerID:    userID,
+				Action:        string(action),
+				State:         string(state),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+func (s *service) DismissReview(ctx context.Context, prID, userID, comment string) (PullRequest, error) {
+	var res PullRequest
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		current, _, err := s.lockForReview(ctx, prID, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.prs.SetReviewState(ctx, prID, userID, ReviewStateDismissed, comment); err != nil {
+			return err
+		}
+		if err := s.loadReviews(ctx, &current); err != nil {
+			return err
+		}
+		res = current
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.ReviewDismissed{
+				PullRequestID: prID,
+				ReviewerID:    userID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+func (s *service) ExplainAssignment(ctx context.Context, prID string) ([]Assignment, error) {
+	current, err := s.prs.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.prs.GetAssignments(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range assignments {
+		for j := range assignments[i].Decisions {
+			d := &assignments[i].Decisions[j]
+			r, ok := s.selectors.replayer(d.Strategy)
+			if !ok {
+				continue
+			}
+			candidates := make([]user.User, 0, len(d.Candidates))
+			for _, id := range d.Candidates {
+				candidates = append(candidates, user.User{ID: id})
+			}
+			d.Replayed = r.Replay(SelectionRequest{
+				TeamName:   d.TeamName,
+				AuthorID:   current.AuthorID,
+				Candidates: candidates,
+				Max:        d.Max,
+				Purpose:    d.Purpose,
+			}, d.Scores, d.Seed)
+			d.Reproducible = r.Deterministic() && slices.Equal(d.Replayed, d.Selected)
+		}
+	}
+	return assignments, nil
+}
+
+func (s *service) recordAssignment(ctx context.Context, prID, operation string, log *decisionLog) error {
+	if len(log.decisions) == 0 {
+		return nil
+	}
+	return s.prs.RecordAssignment(ctx, Assignment{
+		PRID:      prID,
+		Operation: operation,
+		Decisions: log.decisions,
+	})
+}
+
+func (s *service) getAuthor(ctx context.Context, authorID string) (user.User, error) {
+	author, err := s.users.GetByID(ctx, authorID)
+	if err != nil {
+		return user.User{}, err
+	}
+	if author.TeamName == "" {
+		return user.User{}, &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "author has no team",
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+	return author, nil
+}
+
+func (s *service) selectReviewers(ctx context.Context, author user.User, p PullRequest, assigned []string) ([]string, *Staffing, error) {
+	settings, err := s.teams.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	excluded, preferred, err := s.pairRules(ctx, author)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members, err := s.users.GetActiveTeamMembersExcept(ctx, author.TeamName, author.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates := make([]user.User, 0, len(members))
+	for _, u := range members {
+		if !contains(assigned, u.ID) && !contains(excluded, u.ID) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	counts, err := s.openReviewCounts(ctx, candidates)
+	if err != nil {
+		return nil, nil, err
+	}
+	available, atCapacity := splitByCapacity(candidates, counts)
+
+	selector := s.selectors.For(Strategy(settings.ReviewerStrategy))
+	want := settings.MaxReviewers - len(assigned)
+
+	var selected []string
+	if want > 0 && len(preferred) > 0 && !containsAny(assigned, preferred) {
+		selected, err = selector.Select(ctx, SelectionRequest{
+			TeamName:   author.TeamName,
+			AuthorID:   author.ID,
+			Candidates: onlyIn(available, preferred),
+			Max:        1,
+			Purpose:    "preferred_pair",
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(selected) < want {
+		tiers, err := s.preferredReviewers(ctx, author.TeamName, p, members)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, tier := range tiers {
+			if containsAny(assigned, tier.ids) {
+				break
+			}
+			var pool []user.User
+			for _, u := range available {
+				if contains(tier.ids, u.ID) && !contains(selected, u.ID) {
+					pool = append(pool, u)
+				}
+			}
+			if len(pool) == 0 {
+				continue
+			}
+			one, err := selector.Select(ctx, SelectionRequest{
+				TeamName:   author.TeamName,
+				AuthorID:   author.ID,
+				Candidates: pool,
+				Max:        1,
+				Purpose:    tier.purpose,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			selected = append(selected, one...)
+			break
+		}
+	}
+
+	if settings.MentorPairing && len(selected) < want {
+		paired, err := s.pairMentors(ctx, selector, author, members, available, assigned, selected, want-len(selected))
+		if err != nil {
+			return nil, nil, err
+		}
+		selected = append(selected, paired...)
+	}
+
+	rest := make([]user.User, 0, len(available))
+	for _, u := range available {
+		if !contains(selected, u.ID) {
+			rest = append(rest, u)
+		}
+	}
+	more, err := selector.Select(ctx, SelectionRequest{
+		TeamName:   author.TeamName,
+		AuthorID:   author.ID,
+		Candidates: rest,
+		Max:        want - len(selected),
+		Purpose:    "strategy",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	selected = append(selected, more...)
+
+	for _, partner := range settings.PartnerTeams {
+		if len(selected) >= want {
+			break
+		}
+		exclude := append(append(append([]string{}, assigned...), selected...), excluded...)
+		fromPartner, skipped, err := s.selectFromTeam(ctx, selector, partner, author.ID, exclude, want-len(selected))
+		if err != nil {
+			return nil, nil, err
+		}
+		selected = append(selected, fromPartner...)
+		atCapacity = append(atCapacity, skipped...)
+	}
+
+	reason := "not enough active reviewers in team"
+	if len(settings.PartnerTeams) > 0 {
+		reason = "not enough active reviewers in team and partner teams"
+	}
+	if len(atCapacity) > 0 {
+		reason = fmt.Sprintf("%d candidate(s) at review capacity", len(atCapacity))
+	}
+	if len(assigned)+len(selected) < settings.MinReviewers {
+		return nil, nil, &domain.DomainError{
+			Code:       domain.ErrorCodeNoCandidate,
+			Message:    reason,
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+
+	var staffing *Staffing
+	if len(assigned)+len(selected) < settings.MaxReviewers {
+		staffing = &Staffing{
+			Requested:  settings.MaxReviewers,
+			Assigned:   len(assigned) + len(selected),
+			AtCapacity: atCapacity,
+			Reason:     reason,
+		}
+	}
+	return selected, staffing, nil
+}
+
+func (s *service) pairMentors(ctx context.Context, selector ReviewerSelector, author user.User, members, available []user.User, assigned, selected []string, max int) ([]string, error) {
+	levels := map[user.Seniority]bool{}
+	for _, id := range append(append([]string{}, assigned...), selected...) {
+		u, ok := findUser(members, id)
+		if !ok {
+			var err error
+			if u, err = s.users.GetByID(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+		levels[u.Seniority] = true
+	}
+
+	var picked []string
+	for _, level := range []user.Seniority{user.SenioritySenior, user.SeniorityJunior} {
+		if levels[level] || len(picked) >= max {
+			continue
+		}
+		var pool []user.User
+		for _, u := range available {
+			if u.Seniority == level && !contains(selected, u.ID) {
+				pool = append(pool, u)
+			}
+		}
+		one, err := selector.Select(ctx, SelectionRequest{
+			TeamName:   author.TeamName,
+			AuthorID:   author.ID,
+			Candidates: pool,
+			Max:        1,
+			Purpose:    "mentor_" + string(level),
+		})
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, one...)
+	}
+	return picked, nil
+}
+
+func (s *service) selectFromTeam(ctx context.Context, selector ReviewerSelector, teamName, authorID string, exclude []string, max int) ([]string, []string, error) {
+	members, err := s.users.GetActiveTeamMembersExcept(ctx, teamName, authorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates := make([]user.User, 0, len(members))
+	for _, u := range members {
+		if !contains(exclude, u.ID) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	counts, err := s.openReviewCounts(ctx, candidates)
+	if err != nil {
+		return nil, nil, err
+	}
+	available, atCapacity := splitByCapacity(candidates, counts)
+
+	selected, err := selector.Select(ctx, SelectionRequest{
+		TeamName:   teamName,
+		AuthorID:   authorID,
+		Candidates: available,
+		Max:        max,
+		Purpose:    "partner_team",
+	})
+	return selected, atCapacity, err
+}
+
+// fallbackTeams lists where a replacement is searched when the reviewer's
+// own team has no candidate: the author's team, then its partner teams.
+func (s *service) fallbackTeams(ctx context.Context, authorTeam, primary string) ([]string, error) {
+	settings, err := s.teams.GetSettings(ctx, authorTeam)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, t := range append([]string{authorTeam}, settings.PartnerTeams...) {
+		if t != primary && !contains(res, t) {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (s *service) pairRules(ctx context.Context, author user.User) ([]string, []string, error) {
+	rules, err := s.teams.ListPairRules(ctx, author.TeamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	excluded, preferred := pairRulesFor(author.ID, rules)
+	return excluded, preferred, nil
+}
+
+func pairRulesFor(authorID string, rules []team.PairRule) ([]string, []string) {
+	var excluded, preferred []string
+	for _, r := range rules {
+		if r.AuthorID != authorID {
+			continue
+		}
+		switch r.Kind {
+		case team.PairRuleExclude:
+			excluded = append(excluded, r.ReviewerID)
+		case team.PairRulePrefer:
+			preferred = append(preferred, r.ReviewerID)
+		}
+	}
+	return excluded, preferred
+}
+
+type reviewerTier struct {
+	purpose string
+	ids     []string
+}
+
+func (s *service) preferredReviewers(ctx context.Context, teamName string, p PullRequest, members []user.User) ([]reviewerTier, error) {
+	var tiers []reviewerTier
+	if len(p.Files) > 0 {
+		rules, err := s.teams.GetOwnershipRules(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if owners := team.NewOwnership(rules).Owners(p.Files); len(owners) > 0 {
+			tiers = append(tiers, reviewerTier{purpose: "code_owners", ids: owners})
+		}
+	}
+	if len(p.Labels) > 0 {
+		var experts []string
+		for _, u := range members {
+			if sharesTag(u.Expertise, p.Labels) {
+				experts = append(experts, u.ID)
+			}
+		}
+		if len(experts) > 0 {
+			tiers = append(tiers, reviewerTier{purpose: "expertise", ids: experts})
+		}
+	}
+	return tiers, nil
+}
+
+func (s *service) openReviewCounts(ctx context.Context, candidates []user.User) (map[string]int, error) {
+	var limited []string
+	for _, u := range candidates {
+		if u.MaxOpenReviews > 0 {
+			limited = append(limited, u.ID)
+		}
+	}
+	if len(limited) == 0 {
+		return map[string]int{}, nil
+	}
+	return s.prs.CountOpenReviews(ctx, limited)
+}
+
+func (s *service) checkApprovals(ctx context.Context, current PullRequest) error {
+	author, err := s.users.GetByID(ctx, current.AuthorID)
+	if err != nil {
+		return err
+	}
+	settings, err := s.teams.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return err
+	}
+	if settings.RequiredApprovals == 0 {
+		return nil
+	}
+
+	reviews, err := s.prs.GetReviews(ctx, current.ID)
+	if err != nil {
+		return err
+	}
+
+	approved := 0
+	for _, r := range reviews {
+		switch r.State {
+		case ReviewStateChangesRequested:
+			return &domain.DomainError{
+				Code:       domain.ErrorCodeNotApproved,
+				Message:    "changes requested by " + r.ReviewerID,
+				HTTPStatus: http.StatusConflict,
+			}
+		case ReviewStateApproved:
+			approved++
+		}
+	}
+	if approved < settings.RequiredApprovals {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeNotApproved,
+			Message:    fmt.Sprintf("%d of %d required approvals", approved, settings.RequiredApprovals),
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+	return nil
+}
+
+func (s *service) lockForReview(ctx context.Context, prID, userID string) (PullRequest, Review, error) {
+	current, err := s.prs.LockByID(ctx, prID)
+	if err != nil {
+		return PullRequest{}, Review{}, err
+	}
+	if current.Status == StatusMerged {
+		return PullRequest{}, Review{}, &domain.DomainError{
+			Code:       domain.ErrorCodePRMerged,
+			Message:    "cannot review merged PR",
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+	if current.Status != StatusOpen {
+		return PullRequest{}, Review{}, &domain.DomainError{
+			Code:       domain.ErrorCodePRNotOpen,
+			Message:    "cannot review " + string(current.Status) + " PR",
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+
+	reviews, err := s.prs.GetReviews(ctx, prID)
+	if err != nil {
+		return PullRequest{}, Review{}, err
+	}
+	for _, r := range reviews {
+		if r.ReviewerID == userID {
+			return current, r, nil
+		}
+	}
+	return PullRequest{}, Review{}, &domain.DomainError{
+		Code:       domain.ErrorCodeNotAssigned,
+		Message:    "reviewer is not assigned to this PR",
+		HTTPStatus: http.StatusConflict,
+	}
+}
+
+func (s *service) lockForReviewerChange(ctx context.Context, prID string) (PullRequest, error) {
+	current, err := s.prs.LockByID(ctx, prID)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	switch current.Status {
+	case StatusMerged:
+		return PullRequest{}, &domain.DomainError{
+			Code:       domain.ErrorCodePRMerged,
+			Message:    "cannot change reviewers on merged PR",
+			HTTPStatus: http.StatusConflict,
+		}
+	case StatusClosed, StatusDraft:
+		return PullRequest{}, &domain.DomainError{
+			Code:       domain.ErrorCodePRNotOpen,
+			Message:    "cannot change reviewers on " + string(current.Status) + " PR",
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+	return current, nil
+}
+
+func (s *service) loadReviews(ctx context.Context, p *PullRequest) error {
+	revs, err := s.prs.GetReviewers(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	reviews, err := s.prs.GetReviews(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	p.AssignedReviewers = revs
+	p.Reviews = reviews
+	return nil
+}
+
+func leastLoaded(candidates []user.User, load map[string]int) []user.User {
+	if len(candidates) == 0 {
+		return nil
+	}
+	minLoad := load[candidates[0].ID]
+	for _, u := range candidates[1:] {
+		if load[u.ID] < minLoad {
+			minLoad = load[u.ID]
+		}
+	}
+	res := make([]user.User, 0, len(candidates))
+	for _, u := range candidates {
+		if load[u.ID] == minLoad {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+func splitByCapacity(candidates []user.User, counts map[string]int) ([]user.User, []string) {
+	available := make([]user.User, 0, len(candidates))
+	var atCapacity []string
+	for _, u := range candidates {
+		if atReviewCapacity(u, counts) {
+			atCapacity = append(atCapacity, u.ID)
+			continue
+		}
+		available = append(available, u)
+	}
+	return available, atCapacity
+}
+
+func atReviewCapacity(u user.User, counts map[string]int) bool {
+	return u.MaxOpenReviews > 0 && counts[u.ID] >= u.MaxOpenReviews
+}
+
+func reviewCapacityReached(userID string) error {
+	return &domain.DomainError{
+		Code:       domain.ErrorCodeNoCandidate,
+		Message:    "user " + userID + " is at review capacity",
+		HTTPStatus: http.StatusConflict,
+	}
+}
+
+func noReplacementCandidate() error {
+	return &domain.DomainError{
+		Code:       domain.ErrorCodeNoCandidate,
+		Message:    "no active replacement candidate in team",
+		HTTPStatus: http.StatusConflict,
+	}
+}
+
+func invalidTransition(from, to Status) error {
+	return &domain.DomainError{
+		Code:       domain.ErrorCodeInvalidTransition,
+		Message:    fmt.Sprintf("cannot move PR from %s to %s", from, to),
+		HTTPStatus: http.StatusConflict,
+	}
+}
+
+func findUser(list []user.User, id string) (user.User, bool) {
+	for _, u := range list {
+		if u.ID == id {
+			return u, true
+		}
+	}
+	return user.User{}, false
+}
+
+func sameSeniorityFirst(candidates []user.User, level user.Seniority) []user.User {
+	if level != user.SenioritySenior && level != user.SeniorityJunior {
+		return candidates
+	}
+	var same []user.User
+	for _, u := range candidates {
+		if u.Seniority == level {
+			same = append(same, u)
+		}
+	}
+	if len(same) == 0 {
+		return candidates
+	}
+	return same
+}
+
+func onlyIn(candidates []user.User, ids []string) []user.User {
+	var res []user.User
+	for _, u := range candidates {
+		if contains(ids, u.ID) {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if contains(list, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func sharesTag(tags, labels []string) bool {
+	for _, t := range tags {
+		for _, l := range labels {
+			if strings.EqualFold(t, l) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeList(values []string) []string {
+	var res []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !contains(res, v) {
+			res = append(res, v)
+		}
 	}
-	return out
+	return res
 }
 
 func contains(list []string, v string) bool {
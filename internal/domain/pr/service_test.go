@@ -3,12 +3,18 @@ package pr_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"prservice/internal/domain"
 	"prservice/internal/domain/pr"
+	"prservice/internal/domain/stats"
+	"prservice/internal/domain/team"
 	"prservice/internal/domain/user"
 )
 
@@ -19,11 +25,19 @@ func (uowStub) WithinTx(ctx context.Context, fn func(ctx context.Context) error)
 }
 
 type eventBusFake struct {
-	events []domain.Event
+	events  []domain.Event
+	batches int
 }
 
-func (e *eventBusFake) Publish(ctx context.Context, ev domain.Event) {
+func (e *eventBusFake) Publish(ctx context.Context, ev domain.Event) error {
 	e.events = append(e.events, ev)
+	return nil
+}
+
+func (e *eventBusFake) PublishAll(ctx context.Context, evs []domain.Event) error {
+	e.batches++
+	e.events = append(e.events, evs...)
+	return nil
 }
 
 type fixedRand struct{}
@@ -31,9 +45,108 @@ type fixedRand struct{}
 func (fixedRand) Shuffle(n int, swap func(i, j int)) {
 }
 
+func (fixedRand) Intn(n int) int {
+	return 0
+}
+
+type seededRand struct {
+	r *rand.Rand
+}
+
+func newSeededRand(seed int64) *seededRand {
+	return &seededRand{r: rand.New(rand.NewSource(seed))}
+}
+
+func (s *seededRand) Shuffle(n int, swap func(i, j int)) {
+	s.r.Shuffle(n, swap)
+}
+
+func (s *seededRand) Intn(n int) int {
+	return s.r.Intn(n)
+}
+
+func (s *seededRand) WithSeed(seed int64) domain.RandomSource {
+	return newSeededRand(seed)
+}
+
+type teamRepoFake struct {
+	settings map[string]team.Settings
+	rules    map[string][]team.OwnershipRule
+	pairs    []team.PairRule
+}
+
+func newTeamRepoFake() *teamRepoFake {
+	return &teamRepoFake{settings: map[string]team.Settings{}, rules: map[string][]team.OwnershipRule{}}
+}
+
+func (r *teamRepoFake) Exists(ctx context.Context, name string) (bool, error) {
+	_, ok := r.settings[name]
+	return ok, nil
+}
+func (r *teamRepoFake) Create(ctx context.Context, name string, settings team.Settings) error {
+	r.settings[name] = settings
+	return nil
+}
+func (r *teamRepoFake) GetWithMembers(ctx context.Context, name string) (team.Team, error) {
+	return team.Team{Name: name, Settings: r.settings[name]}, nil
+}
+func (r *teamRepoFake) GetSettings(ctx context.Context, name string) (team.Settings, error) {
+	if st, ok := r.settings[name]; ok {
+		return st, nil
+	}
+	return team.DefaultSettings(), nil
+}
+func (r *teamRepoFake) UpdateSettings(ctx context.Context, name string, settings team.Settings) error {
+	r.settings[name] = settings
+	return nil
+}
+func (r *teamRepoFake) GetOwnershipRules(ctx context.Context, name string) ([]team.OwnershipRule, error) {
+	return r.rules[name], nil
+}
+func (r *teamRepoFake) SetOwnershipRules(ctx context.Context, name string, rules []team.OwnershipRule) error {
+	r.rules[name] = rules
+	return nil
+}
+func (r *teamRepoFake) AddPairRule(ctx context.Context, rule team.PairRule) (team.PairRule, error) {
+	rule.ID = int64(len(r.pairs) + 1)
+	r.pairs = append(r.pairs, rule)
+	return rule, nil
+}
+func (r *teamRepoFake) ListPairRules(ctx context.Context, name string) ([]team.PairRule, error) {
+	var res []team.PairRule
+	for _, rule := range r.pairs {
+		if rule.TeamName == name && rule.ID > 0 {
+			res = append(res, rule)
+		}
+	}
+	return res, nil
+}
+func (r *teamRepoFake) DeletePairRule(ctx context.Context, name string, id int64) error {
+	for i, rule := range r.pairs {
+		if rule.TeamName == name && rule.ID == id {
+			r.pairs[i].ID = 0
+			return nil
+		}
+	}
+	return &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "pair rule not found", HTTPStatus: 404}
+}
+
+func newTestService(prs pr.Repository, users user.Repository, teams team.Repository, events domain.EventPublisher, rnd domain.RandomSource) pr.Service {
+	selectors, err := pr.NewSelectors(pr.StrategyRandom, map[pr.Strategy]pr.ReviewerSelector{
+		pr.StrategyRandom:     pr.NewRandomSelector(rnd),
+		pr.StrategyRoundRobin: pr.NewRoundRobinSelector(),
+		pr.StrategyWeighted:   pr.NewWeightedSelector(rnd),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return pr.NewService(uowStub{}, prs, users, teams, events, selectors)
+}
+
 type userRepoFake struct {
 	byID        map[string]user.User
 	teamMembers map[string][]user.User
+	away        []user.Unavailability
 }
 
 func newUserRepoFake() *userRepoFake {
@@ -74,26 +187,75 @@ func (r *userRepoFake) GetByID(ctx context.Context, userID string) (user.User, e
 	}
 	return u, nil
 }
+func (r *userRepoFake) FindByHandle(ctx context.Context, handle string) ([]user.User, error) {
+	if u, ok := r.byID[handle]; ok {
+		return []user.User{u}, nil
+	}
+	var res []user.User
+	for _, u := range r.byID {
+		if strings.EqualFold(u.Username, handle) {
+			res = append(res, u)
+		}
+	}
+	return res, nil
+}
 func (r *userRepoFake) GetActiveTeamMembersExcept(ctx context.Context, teamName, excludeUserID string) ([]user.User, error) {
 	var res []user.User
+	now := time.Now()
 	for _, u := range r.teamMembers[teamName] {
 		if !u.IsActive || u.ID == excludeUserID {
 			continue
 		}
+		if r.isAway(u.ID, now) {
+			continue
+		}
 		res = append(res, u)
 	}
 	return res, nil
 }
+func (r *userRepoFake) isAway(userID string, at time.Time) bool {
+	for _, a := range r.away {
+		if a.UserID == userID && !at.Before(a.StartsAt) && at.Before(a.EndsAt) {
+			return true
+		}
+	}
+	return false
+}
+func (r *userRepoFake) AddUnavailability(ctx context.Context, u user.Unavailability) (user.Unavailability, error) {
+	u.ID = int64(len(r.away) + 1)
+	r.away = append(r.away, u)
+	return u, nil
+}
+func (r *userRepoFake) ListUnavailability(ctx context.Context, userID string) ([]user.Unavailability, error) {
+	var res []user.Unavailability
+	now := time.Now()
+	for _, a := range r.away {
+		if a.UserID == userID && a.EndsAt.After(now) {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+func (r *userRepoFake) DeleteUnavailability(ctx context.Context, userID string, id int64) error {
+	return nil
+}
 
 type prRepoFake struct {
-	prs       map[string]pr.PullRequest
-	reviewers map[string][]string
+	prs         map[string]pr.PullRequest
+	reviewers   map[string][]string
+	states      map[string]map[string]pr.Review
+	declines    []pr.Decline
+	created     []string
+	assignments []pr.Assignment
+	locks       int
+	writes      int
 }
 
 func newPRRepoFake() *prRepoFake {
 	return &prRepoFake{
 		prs:       map[string]pr.PullRequest{},
 		reviewers: map[string][]string{},
+		states:    map[string]map[string]pr.Review{},
 	}
 }
 
@@ -103,16 +265,48 @@ func (r *prRepoFake) CreateWithReviewers(ctx context.Context, p pr.PullRequest)
 	}
 	r.prs[p.ID] = p
 	r.reviewers[p.ID] = append([]string{}, p.AssignedReviewers...)
+	r.created = append(r.created, p.ID)
+	return p, nil
+}
+func (r *prRepoFake) GetByID(ctx context.Context, id string) (pr.PullRequest, error) {
+	p, ok := r.prs[id]
+	if !ok {
+		return pr.PullRequest{}, &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "pull request not found", HTTPStatus: 404}
+	}
 	return p, nil
 }
 func (r *prRepoFake) LockByID(ctx context.Context, id string) (pr.PullRequest, error) {
+	r.locks++
 	p, ok := r.prs[id]
 	if !ok {
 		return pr.PullRequest{}, &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "pull request not found", HTTPStatus: 404}
 	}
 	return p, nil
 }
-func (r *prRepoFake) UpdateStatusMerged(ctx context.Context, id string) (pr.PullRequest, error) {
+func (r *prRepoFake) LockOpenByReviewers(ctx context.Context, userIDs []string) ([]pr.PullRequest, error) {
+	r.locks++
+	var ids []string
+	for id, p := range r.prs {
+		if p.Status != pr.StatusOpen {
+			continue
+		}
+		for _, uid := range userIDs {
+			if contains(r.reviewers[id], uid) {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	res := make([]pr.PullRequest, 0, len(ids))
+	for _, id := range ids {
+		p := r.prs[id]
+		p.AssignedReviewers = append([]string{}, r.reviewers[id]...)
+		res = append(res, p)
+	}
+	return res, nil
+}
+func (r *prRepoFake) UpdateStatusMerged(ctx context.Context, id string, forced bool) (pr.PullRequest, error) {
 	p, ok := r.prs[id]
 	if !ok {
 		return pr.PullRequest{}, &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "pull request not found", HTTPStatus: 404}
@@ -120,6 +314,21 @@ func (r *prRepoFake) UpdateStatusMerged(ctx context.Context, id string) (pr.Pull
 	now := time.Now().UTC()
 	p.Status = pr.StatusMerged
 	p.MergedAt = &now
+	p.MergeForced = forced
+	r.prs[id] = p
+	return p, nil
+}
+func (r *prRepoFake) UpdateStatus(ctx context.Context, id string, status pr.Status) (pr.PullRequest, error) {
+	p, ok := r.prs[id]
+	if !ok {
+		return pr.PullRequest{}, &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "pull request not found", HTTPStatus: 404}
+	}
+	p.Status = status
+	p.ClosedAt = nil
+	if status == pr.StatusClosed {
+		now := time.Now().UTC()
+		p.ClosedAt = &now
+	}
 	r.prs[id] = p
 	return p, nil
 }
@@ -130,12 +339,32 @@ func (r *prRepoFake) SetReviewers(ctx context.Context, prID string, reviewerIDs
 	if _, ok := r.prs[prID]; !ok {
 		return &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "pull request not found", HTTPStatus: 404}
 	}
+	r.writes++
 	r.reviewers[prID] = append([]string{}, reviewerIDs...)
 	p := r.prs[prID]
 	p.AssignedReviewers = append([]string{}, reviewerIDs...)
 	r.prs[prID] = p
 	return nil
 }
+func (r *prRepoFake) ReplaceReviewers(ctx context.Context, changes []pr.ReviewerChange) error {
+	r.writes++
+	for _, c := range changes {
+		var next []string
+		for _, id := range r.reviewers[c.PRID] {
+			if id != c.OldUserID {
+				next = append(next, id)
+			}
+		}
+		if c.NewUserID != "" && !contains(next, c.NewUserID) {
+			next = append(next, c.NewUserID)
+		}
+		r.reviewers[c.PRID] = next
+		p := r.prs[c.PRID]
+		p.AssignedReviewers = append([]string{}, next...)
+		r.prs[c.PRID] = p
+	}
+	return nil
+}
 func (r *prRepoFake) UserIsReviewer(ctx context.Context, prID, userID string) (bool, error) {
 	for _, id := range r.reviewers[prID] {
 		if id == userID {
@@ -158,8 +387,91 @@ func (r *prRepoFake) GetUserPRs(ctx context.Context, userID string) ([]pr.PullRe
 	return res, nil
 }
 
+func (r *prRepoFake) GetReviews(ctx context.Context, prID string) ([]pr.Review, error) {
+	var res []pr.Review
+	for _, id := range r.reviewers[prID] {
+		rv, ok := r.states[prID][id]
+		if !ok {
+			rv = pr.Review{ReviewerID: id, State: pr.ReviewStatePending}
+		}
+		res = append(res, rv)
+	}
+	return res, nil
+}
+func (r *prRepoFake) SetReviewState(ctx context.Context, prID, userID string, state pr.ReviewState, comment string) error {
+	if r.states[prID] == nil {
+		r.states[prID] = map[string]pr.Review{}
+	}
+	rv := r.states[prID][userID]
+	rv.ReviewerID = userID
+	rv.State = state
+	if comment != "" {
+		rv.Comment = comment
+	}
+	r.states[prID][userID] = rv
+	return nil
+}
+func (r *prRepoFake) RecordDecline(ctx context.Context, d pr.Decline) error {
+	r.declines = append(r.declines, d)
+	return nil
+}
+func (r *prRepoFake) GetDeclinedUsers(ctx context.Context, prID string) ([]string, error) {
+	var res []string
+	for _, d := range r.declines {
+		if d.PRID == prID {
+			res = append(res, d.UserID)
+		}
+	}
+	return res, nil
+}
+func (r *prRepoFake) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	res := map[string]int{}
+	for id, p := range r.prs {
+		if p.Status != pr.StatusOpen {
+			continue
+		}
+		for _, rid := range r.reviewers[id] {
+			if contains(userIDs, rid) {
+				res[rid]++
+			}
+		}
+	}
+	return res, nil
+}
+func (r *prRepoFake) RecordAssignment(ctx context.Context, a pr.Assignment) error {
+	a.ID = int64(len(r.assignments) + 1)
+	r.assignments = append(r.assignments, a)
+	return nil
+}
+func (r *prRepoFake) RecordAssignments(ctx context.Context, assignments []pr.Assignment) error {
+	for _, a := range assignments {
+		if err := r.RecordAssignment(ctx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (r *prRepoFake) GetAssignments(ctx context.Context, prID string) ([]pr.Assignment, error) {
+	var res []pr.Assignment
+	for _, a := range r.assignments {
+		if a.PRID == prID {
+			a.Decisions = append([]pr.Decision(nil), a.Decisions...)
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+func (r *prRepoFake) RecentReviewers(ctx context.Context, authorID string, limit int) ([][]string, error) {
+	var res [][]string
+	for i := len(r.created) - 1; i >= 0 && len(res) < limit; i-- {
+		if r.prs[r.created[i]].AuthorID == authorID {
+			res = append(res, r.reviewers[r.created[i]])
+		}
+	}
+	return res, nil
+}
+
 func TestService_Create_AssignsUpToTwoActiveFromAuthorTeam(t *testing.T) {
-	uow := uowStub{}
 	users := newUserRepoFake()
 	prs := newPRRepoFake()
 	events := &eventBusFake{}
@@ -172,11 +484,11 @@ func TestService_Create_AssignsUpToTwoActiveFromAuthorTeam(t *testing.T) {
 		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: false},
 		{ID: "u5", Username: "Dan", TeamName: "backend", IsActive: true},
 	})
-	svc := pr.NewService(uow, prs, users, events, rnd)
+	svc := newTestService(prs, users, newTeamRepoFake(), events, rnd)
 
 	users.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}
 
-	p, err := svc.Create(context.Background(), "pr-1", "Add search", "u1")
+	p, err := svc.Create(context.Background(), pr.CreateParams{ID: "pr-1", Name: "Add search", AuthorID: "u1"})
 	if err != nil {
 		t.Fatalf("Create error: %v", err)
 	}
@@ -194,13 +506,12 @@ func TestService_Create_AssignsUpToTwoActiveFromAuthorTeam(t *testing.T) {
 			t.Fatalf("inactive must not be reviewer")
 		}
 	}
-	if len(events.events) != 1 || events.events[0].Type != "pr.created" {
+	if len(events.events) != 1 || events.events[0].EventType() != "pr.created" {
 		t.Fatalf("expected pr.created event, got %+v", events.events)
 	}
 }
 
 func TestService_Create_AuthorWithoutTeam_ReturnsNotFound(t *testing.T) {
-	uow := uowStub{}
 	users := newUserRepoFake()
 	prs := newPRRepoFake()
 	events := &eventBusFake{}
@@ -208,8 +519,8 @@ func TestService_Create_AuthorWithoutTeam_ReturnsNotFound(t *testing.T) {
 
 	users.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "", IsActive: true}
 
-	svc := pr.NewService(uow, prs, users, events, rnd)
-	_, err := svc.Create(context.Background(), "pr-err", "X", "u1")
+	svc := newTestService(prs, users, newTeamRepoFake(), events, rnd)
+	_, err := svc.Create(context.Background(), pr.CreateParams{ID: "pr-err", Name: "X", AuthorID: "u1"})
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -220,29 +531,29 @@ func TestService_Create_AuthorWithoutTeam_ReturnsNotFound(t *testing.T) {
 }
 
 func TestService_Merge_NormalAndIdempotent(t *testing.T) {
-	uow := uowStub{}
 	users := newUserRepoFake()
 	prs := newPRRepoFake()
 	events := &eventBusFake{}
 	rnd := fixedRand{}
-	svc := pr.NewService(uow, prs, users, events, rnd)
+	svc := newTestService(prs, users, newTeamRepoFake(), events, rnd)
 
+	users.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}
 	prs.prs["pr-2"] = pr.PullRequest{ID: "pr-2", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
 	prs.reviewers["pr-2"] = []string{"u2", "u3"}
 
-	p, err := svc.Merge(context.Background(), "pr-2")
+	p, err := svc.Merge(context.Background(), "pr-2", false)
 	if err != nil {
 		t.Fatalf("merge error: %v", err)
 	}
 	if p.Status != pr.StatusMerged || p.MergedAt == nil {
 		t.Fatalf("expected MERGED with mergedAt, got %+v", p)
 	}
-	if len(events.events) != 1 || events.events[0].Type != "pr.merged" {
+	if len(events.events) != 1 || events.events[0].EventType() != "pr.merged" {
 		t.Fatalf("expected one pr.merged event, got %+v", events.events)
 	}
 
 	events.events = nil
-	p2, err := svc.Merge(context.Background(), "pr-2")
+	p2, err := svc.Merge(context.Background(), "pr-2", false)
 	if err != nil {
 		t.Fatalf("idempotent merge error: %v", err)
 	}
@@ -255,12 +566,11 @@ func TestService_Merge_NormalAndIdempotent(t *testing.T) {
 }
 
 func TestService_Reassign_Success(t *testing.T) {
-	uow := uowStub{}
 	users := newUserRepoFake()
 	prs := newPRRepoFake()
 	events := &eventBusFake{}
 	rnd := fixedRand{}
-	svc := pr.NewService(uow, prs, users, events, rnd)
+	svc := newTestService(prs, users, newTeamRepoFake(), events, rnd)
 
 	users.UpsertInTeam(context.Background(), "backend", []user.User{
 		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
@@ -281,7 +591,7 @@ func TestService_Reassign_Success(t *testing.T) {
 		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
 	}
 
-	p, replacedBy, err := svc.ReassignReviewer(context.Background(), "pr-3", "u2")
+	p, replacedBy, err := svc.ReassignReviewer(context.Background(), "pr-3", "u2", "")
 	if err != nil {
 		t.Fatalf("reassign error: %v", err)
 	}
@@ -291,21 +601,24 @@ func TestService_Reassign_Success(t *testing.T) {
 	if !contains(p.AssignedReviewers, "u4") || contains(p.AssignedReviewers, "u2") {
 		t.Fatalf("reviewers not replaced correctly: %v", p.AssignedReviewers)
 	}
-	if len(events.events) == 0 || events.events[0].Type != "pr.reassign" {
-		t.Fatalf("expected pr.reassign event, got %+v", events.events)
+	if len(events.events) == 0 {
+		t.Fatalf("expected pr.reassign event")
+	}
+	ev, ok := events.events[0].(domain.ReviewerReassigned)
+	if !ok || ev.PullRequestID != "pr-3" || ev.OldReviewerID != "u2" || ev.NewReviewerID != "u4" || ev.Manual {
+		t.Fatalf("unexpected pr.reassign event %+v", events.events[0])
 	}
 }
 
 func TestService_Reassign_Errors(t *testing.T) {
-	uow := uowStub{}
 	users := newUserRepoFake()
 	prs := newPRRepoFake()
 	events := &eventBusFake{}
 	rnd := fixedRand{}
-	svc := pr.NewService(uow, prs, users, events, rnd)
+	svc := newTestService(prs, users, newTeamRepoFake(), events, rnd)
 
 	prs.prs["pr-m"] = pr.PullRequest{ID: "pr-m", Name: "X", AuthorID: "u1", Status: pr.StatusMerged}
-	_, _, err := svc.ReassignReviewer(context.Background(), "pr-m", "u2")
+	_, _, err := svc.ReassignReviewer(context.Background(), "pr-m", "u2", "")
 	if !isDomainErr(err, domain.ErrorCodePRMerged) {
 		t.Fatalf("want PR_MERGED, got %v", err)
 	}
@@ -313,36 +626,36 @@ func TestService_Reassign_Errors(t *testing.T) {
 	prs.prs["pr-x"] = pr.PullRequest{ID: "pr-x", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
 	prs.reviewers["pr-x"] = []string{"u2"}
 	users.byID["u2"] = user.User{ID: "u2", Username: "Bob", TeamName: "", IsActive: true}
-	_, _, err = svc.ReassignReviewer(context.Background(), "pr-x", "u2")
+	_, _, err = svc.ReassignReviewer(context.Background(), "pr-x", "u2", "")
 	if !isDomainErr(err, domain.ErrorCodeNotFound) {
 		t.Fatalf("want NOT_FOUND (no team), got %v", err)
 	}
 
 	users.byID["u2"] = user.User{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true}
 	prs.reviewers["pr-x"] = []string{"u3"}
-	_, _, err = svc.ReassignReviewer(context.Background(), "pr-x", "u2")
+	_, _, err = svc.ReassignReviewer(context.Background(), "pr-x", "u2", "")
 	if !isDomainErr(err, domain.ErrorCodeNotAssigned) {
 		t.Fatalf("want NOT_ASSIGNED, got %v", err)
 	}
 
 	prs.reviewers["pr-x"] = []string{"u2"}
+	users.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}
 	users.teamMembers["backend"] = []user.User{
 		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
 		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
 	}
-	_, _, err = svc.ReassignReviewer(context.Background(), "pr-x", "u2")
+	_, _, err = svc.ReassignReviewer(context.Background(), "pr-x", "u2", "")
 	if !isDomainErr(err, domain.ErrorCodeNoCandidate) {
 		t.Fatalf("want NO_CANDIDATE, got %v", err)
 	}
 }
 
 func TestService_GetUserReviews(t *testing.T) {
-	uow := uowStub{}
 	users := newUserRepoFake()
 	prs := newPRRepoFake()
 	events := &eventBusFake{}
 	rnd := fixedRand{}
-	svc := pr.NewService(uow, prs, users, events, rnd)
+	svc := newTestService(prs, users, newTeamRepoFake(), events, rnd)
 
 	prs.prs["pr-a"] = pr.PullRequest{ID: "pr-a", Name: "A", AuthorID: "u1", Status: pr.StatusOpen}
 	prs.prs["pr-b"] = pr.PullRequest{ID: "pr-b", Name: "B", AuthorID: "u2", Status: pr.StatusOpen}
@@ -358,16 +671,1100 @@ func TestService_GetUserReviews(t *testing.T) {
 	}
 }
 
-func isDomainErr(err error, code domain.ErrorCode) bool {
-	var de *domain.DomainError
-	return errors.As(err, &de) && de.Code == code
+func TestService_Create_UsesTeamRoundRobinStrategy(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	events := &eventBusFake{}
+
+	users.UpsertInTeam(context.Background(), "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+	})
+	teams.settings["backend"] = team.Settings{ReviewerStrategy: string(pr.StrategyRoundRobin), MaxReviewers: 2}
+	svc := newTestService(prs, users, teams, events, fixedRand{})
+
+	p1, err := svc.Create(context.Background(), pr.CreateParams{ID: "pr-1", Name: "A", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	p2, err := svc.Create(context.Background(), pr.CreateParams{ID: "pr-2", Name: "B", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if got := p1.AssignedReviewers; len(got) != 2 || got[0] != "u2" || got[1] != "u3" {
+		t.Fatalf("first PR: want [u2 u3], got %v", got)
+	}
+	if got := p2.AssignedReviewers; len(got) != 2 || got[0] != "u4" || got[1] != "u2" {
+		t.Fatalf("second PR: want [u4 u2], got %v", got)
+	}
 }
 
-func contains(list []string, v string) bool {
-	for _, x := range list {
-		if x == v {
-			return true
-		}
+func TestService_Create_HonorsTeamReviewerCount(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	events := &eventBusFake{}
+
+	users.UpsertInTeam(context.Background(), "platform", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "platform", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "platform", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "platform", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "platform", IsActive: true},
+		{ID: "u5", Username: "Dan", TeamName: "platform", IsActive: false},
+	})
+	teams.settings["platform"] = team.Settings{MinReviewers: 3, MaxReviewers: 3}
+	svc := newTestService(prs, users, teams, events, fixedRand{})
+
+	p, err := svc.Create(context.Background(), pr.CreateParams{ID: "pr-1", Name: "A", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(p.AssignedReviewers) != 3 {
+		t.Fatalf("want 3 reviewers, got %v", p.AssignedReviewers)
+	}
+
+	users.SetActive(context.Background(), "u4", false)
+	_, err = svc.Create(context.Background(), pr.CreateParams{ID: "pr-2", Name: "B", AuthorID: "u1"})
+	if !isDomainErr(err, domain.ErrorCodeNoCandidate) {
+		t.Fatalf("want NO_CANDIDATE when min not reachable, got %v", err)
+	}
+}
+
+func TestService_SubmitReview(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	events := &eventBusFake{}
+	svc := newTestService(prs, users, newTeamRepoFake(), events, fixedRand{})
+
+	prs.prs["pr-r"] = pr.PullRequest{ID: "pr-r", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-r"] = []string{"u2", "u3"}
+
+	p, err := svc.SubmitReview(context.Background(), "pr-r", "u2", pr.ReviewActionApprove, "")
+	if err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if st := reviewState(p, "u2"); st != pr.ReviewStateApproved {
+		t.Fatalf("want APPROVED for u2, got %s", st)
+	}
+	if st := reviewState(p, "u3"); st != pr.ReviewStatePending {
+		t.Fatalf("want PENDING for u3, got %s", st)
+	}
+
+	p, err = svc.SubmitReview(context.Background(), "pr-r", "u2", pr.ReviewActionComment, "nit: naming")
+	if err != nil {
+		t.Fatalf("SubmitReview comment: %v", err)
+	}
+	if st := reviewState(p, "u2"); st != pr.ReviewStateApproved {
+		t.Fatalf("comment must keep APPROVED, got %s", st)
+	}
+
+	p, err = svc.DismissReview(context.Background(), "pr-r", "u2", "stale")
+	if err != nil {
+		t.Fatalf("DismissReview: %v", err)
+	}
+	if st := reviewState(p, "u2"); st != pr.ReviewStateDismissed {
+		t.Fatalf("want DISMISSED for u2, got %s", st)
+	}
+	if len(events.events) != 3 || events.events[2].EventType() != "pr.review_dismissed" {
+		t.Fatalf("unexpected events: %+v", events.events)
+	}
+
+	_, err = svc.SubmitReview(context.Background(), "pr-r", "u9", pr.ReviewActionApprove, "")
+	if !isDomainErr(err, domain.ErrorCodeNotAssigned) {
+		t.Fatalf("want NOT_ASSIGNED, got %v", err)
+	}
+
+	prs.prs["pr-m"] = pr.PullRequest{ID: "pr-m", Name: "X", AuthorID: "u1", Status: pr.StatusMerged}
+	prs.reviewers["pr-m"] = []string{"u2"}
+	_, err = svc.SubmitReview(context.Background(), "pr-m", "u2", pr.ReviewActionApprove, "")
+	if !isDomainErr(err, domain.ErrorCodePRMerged) {
+		t.Fatalf("want PR_MERGED, got %v", err)
+	}
+}
+
+func TestService_Merge_RequiresApprovals(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	events := &eventBusFake{}
+	svc := newTestService(prs, users, teams, events, fixedRand{})
+
+	users.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}
+	teams.settings["backend"] = team.Settings{MaxReviewers: 2, RequiredApprovals: 2}
+	prs.prs["pr-g"] = pr.PullRequest{ID: "pr-g", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-g"] = []string{"u2", "u3"}
+
+	_ = prs.SetReviewState(context.Background(), "pr-g", "u2", pr.ReviewStateApproved, "")
+	_, err := svc.Merge(context.Background(), "pr-g", false)
+	if !isDomainErr(err, domain.ErrorCodeNotApproved) {
+		t.Fatalf("want NOT_APPROVED with 1 of 2 approvals, got %v", err)
+	}
+
+	_ = prs.SetReviewState(context.Background(), "pr-g", "u3", pr.ReviewStateChangesRequested, "")
+	_, err = svc.Merge(context.Background(), "pr-g", false)
+	if !isDomainErr(err, domain.ErrorCodeNotApproved) {
+		t.Fatalf("want NOT_APPROVED with changes requested, got %v", err)
+	}
+
+	_ = prs.SetReviewState(context.Background(), "pr-g", "u3", pr.ReviewStateApproved, "")
+	p, err := svc.Merge(context.Background(), "pr-g", false)
+	if err != nil {
+		t.Fatalf("merge with approvals: %v", err)
+	}
+	if p.Status != pr.StatusMerged || p.MergeForced {
+		t.Fatalf("expected regular merge, got %+v", p)
+	}
+
+	prs.prs["pr-f"] = pr.PullRequest{ID: "pr-f", Name: "Y", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-f"] = []string{"u2"}
+	p, err = svc.Merge(context.Background(), "pr-f", true)
+	if err != nil {
+		t.Fatalf("forced merge: %v", err)
+	}
+	if !p.MergeForced {
+		t.Fatalf("forced merge must be recorded")
+	}
+	last := events.events[len(events.events)-1]
+	if merged, ok := last.(domain.PRMerged); !ok || !merged.Forced || merged.PullRequestID != p.ID {
+		t.Fatalf("expected forced pr.merged event, got %+v", last)
+	}
+}
+
+func TestService_Lifecycle(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	events := &eventBusFake{}
+	svc := newTestService(prs, users, newTeamRepoFake(), events, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+	})
+
+	p, err := svc.Create(ctx, pr.CreateParams{ID: "pr-d", Name: "WIP", AuthorID: "u1", Draft: true})
+	if err != nil {
+		t.Fatalf("Create draft: %v", err)
+	}
+	if p.Status != pr.StatusDraft || len(p.AssignedReviewers) != 0 {
+		t.Fatalf("draft must have no reviewers, got %+v", p)
+	}
+
+	if _, err := svc.Merge(ctx, "pr-d", true); !isDomainErr(err, domain.ErrorCodeInvalidTransition) {
+		t.Fatalf("want INVALID_TRANSITION merging draft, got %v", err)
+	}
+
+	p, err = svc.MarkReady(ctx, "pr-d")
+	if err != nil {
+		t.Fatalf("MarkReady: %v", err)
+	}
+	if p.Status != pr.StatusOpen || len(p.AssignedReviewers) != 2 {
+		t.Fatalf("ready PR must be OPEN with reviewers, got %+v", p)
+	}
+
+	p, err = svc.Close(ctx, "pr-d")
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if p.Status != pr.StatusClosed || p.ClosedAt == nil {
+		t.Fatalf("expected CLOSED with closedAt, got %+v", p)
+	}
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-d", "u2", ""); !isDomainErr(err, domain.ErrorCodePRNotOpen) {
+		t.Fatalf("want PR_NOT_OPEN reassigning closed PR, got %v", err)
+	}
+	if _, err := svc.MarkReady(ctx, "pr-d"); !isDomainErr(err, domain.ErrorCodeInvalidTransition) {
+		t.Fatalf("want INVALID_TRANSITION marking closed PR ready, got %v", err)
+	}
+
+	p, err = svc.Reopen(ctx, "pr-d")
+	if err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	if p.Status != pr.StatusOpen || p.ClosedAt != nil || len(p.AssignedReviewers) != 2 {
+		t.Fatalf("reopened PR must be OPEN and keep reviewers, got %+v", p)
+	}
+
+	if _, err := svc.Merge(ctx, "pr-d", false); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if _, err := svc.Close(ctx, "pr-d"); !isDomainErr(err, domain.ErrorCodeInvalidTransition) {
+		t.Fatalf("want INVALID_TRANSITION closing merged PR, got %v", err)
+	}
+
+	var types []string
+	for _, e := range events.events {
+		types = append(types, e.EventType())
+	}
+	want := []string{"pr.created", "pr.ready", "pr.closed", "pr.reopened", "pr.merged"}
+	if len(types) != len(want) {
+		t.Fatalf("want events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("want events %v, got %v", want, types)
+		}
+	}
+}
+
+func reviewState(p pr.PullRequest, userID string) pr.ReviewState {
+	for _, r := range p.Reviews {
+		if r.ReviewerID == userID {
+			return r.State
+		}
+	}
+	return ""
+}
+
+type lastRand struct{}
+
+func (lastRand) Shuffle(n int, swap func(i, j int)) {}
+func (lastRand) Intn(n int) int                     { return n - 1 }
+
+func TestWeightedSelector_PrefersHeavierCandidates(t *testing.T) {
+	sel := pr.NewWeightedSelector(lastRand{})
+
+	got, err := sel.Select(context.Background(), pr.SelectionRequest{
+		TeamName: "backend",
+		Candidates: []user.User{
+			{ID: "u2", ReviewWeight: 1},
+			{ID: "u3", ReviewWeight: 5},
+		},
+		Max: 1,
+	})
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if len(got) != 1 || got[0] != "u3" {
+		t.Fatalf("want [u3], got %v", got)
+	}
+}
+
+type statsRepoFake struct {
+	users []stats.UserAssignmentStat
+}
+
+func (r *statsRepoFake) GetUserAssignmentStats(ctx context.Context, teamName *string) ([]stats.UserAssignmentStat, error) {
+	return r.users, nil
+}
+func (r *statsRepoFake) GetPRAssignmentStats(ctx context.Context) ([]stats.PRAssignmentStat, error) {
+	return nil, nil
+}
+func (r *statsRepoFake) GetReviewDeclines(ctx context.Context, teamName *string) ([]stats.ReviewDecline, error) {
+	return nil, nil
+}
+
+func TestLeastLoadedSelector_PrefersFewestOpenReviews(t *testing.T) {
+	sr := &statsRepoFake{users: []stats.UserAssignmentStat{
+		{UserID: "u2", AssignedOpen: 3},
+		{UserID: "u3", AssignedOpen: 0},
+		{UserID: "u4", AssignedOpen: 1},
+	}}
+	sel := pr.NewLeastLoadedSelector(sr, fixedRand{})
+
+	got, err := sel.Select(context.Background(), pr.SelectionRequest{
+		TeamName: "backend",
+		Candidates: []user.User{
+			{ID: "u2"}, {ID: "u3"}, {ID: "u4"}, {ID: "u5"},
+		},
+		Max: 2,
+	})
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if len(got) != 2 || got[0] != "u3" || got[1] != "u5" {
+		t.Fatalf("want [u3 u5], got %v", got)
+	}
+}
+
+func TestRotationSelector_AvoidsRecentPairings(t *testing.T) {
+	ctx := context.Background()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	prs.CreateWithReviewers(ctx, pr.PullRequest{ID: "pr-a", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"}})
+	prs.CreateWithReviewers(ctx, pr.PullRequest{ID: "pr-b", AuthorID: "u1", AssignedReviewers: []string{"u3", "u4"}})
+	prs.CreateWithReviewers(ctx, pr.PullRequest{ID: "pr-c", AuthorID: "u9", AssignedReviewers: []string{"u5"}})
+
+	sel := pr.NewRotationSelector(prs, teams, fixedRand{}, 0)
+	req := pr.SelectionRequest{
+		TeamName:   "backend",
+		AuthorID:   "u1",
+		Candidates: []user.User{{ID: "u2"}, {ID: "u3"}, {ID: "u4"}, {ID: "u5"}},
+		Max:        2,
+	}
+
+	got, err := sel.Select(ctx, req)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if len(got) != 2 || got[0] != "u5" || got[1] != "u2" {
+		t.Fatalf("want [u5 u2], got %v", got)
+	}
+
+	req.Max = 4
+	got, _ = sel.Select(ctx, req)
+	if got[3] != "u3" {
+		t.Fatalf("most frequent recent reviewer must come last, got %v", got)
+	}
+
+	settings := team.DefaultSettings()
+	settings.RotationWindow = 1
+	teams.settings["backend"] = settings
+
+	got, _ = sel.Select(ctx, req)
+	if got[0] != "u2" || got[1] != "u5" {
+		t.Fatalf("with window 1 only the latest PR counts, got %v", got)
+	}
+}
+
+func isDomainErr(err error, code domain.ErrorCode) bool {
+	var de *domain.DomainError
+	return errors.As(err, &de) && de.Code == code
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestService_ReassignOpenReviews(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	events := &eventBusFake{}
+	svc := newTestService(prs, users, newTeamRepoFake(), events, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "John", TeamName: "backend", IsActive: true},
+	})
+	prs.prs["pr-1"] = pr.PullRequest{ID: "pr-1", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-1"] = []string{"u2", "u3"}
+	prs.prs["pr-2"] = pr.PullRequest{ID: "pr-2", AuthorID: "u4", Status: pr.StatusOpen}
+	prs.reviewers["pr-2"] = []string{"u1", "u2", "u3"}
+	prs.prs["pr-3"] = pr.PullRequest{ID: "pr-3", AuthorID: "u1", Status: pr.StatusMerged}
+	prs.reviewers["pr-3"] = []string{"u2"}
+
+	if _, err := users.SetActive(ctx, "u2", false); err != nil {
+		t.Fatalf("SetActive: %v", err)
+	}
+
+	report, err := svc.ReassignOpenReviews(ctx, "u2")
+	if err != nil {
+		t.Fatalf("ReassignOpenReviews: %v", err)
+	}
+
+	if len(report.Reassigned) != 1 || report.Reassigned[0].PRID != "pr-1" || report.Reassigned[0].ReplacedBy != "u4" {
+		t.Fatalf("expected pr-1 reassigned to u4, got %+v", report.Reassigned)
+	}
+	if len(report.WithoutCandidate) != 1 || report.WithoutCandidate[0].PRID != "pr-2" {
+		t.Fatalf("expected pr-2 without candidate, got %+v", report.WithoutCandidate)
+	}
+	for _, id := range []string{"pr-1", "pr-2"} {
+		if contains(prs.reviewers[id], "u2") {
+			t.Fatalf("u2 must be removed from %s, got %v", id, prs.reviewers[id])
+		}
+	}
+	if !contains(prs.reviewers["pr-3"], "u2") {
+		t.Fatalf("merged PR must keep its reviewers")
+	}
+}
+
+func TestService_RedistributeReviews_Balanced(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	events := &eventBusFake{}
+	svc := newTestService(prs, users, newTeamRepoFake(), events, fixedRand{})
+	ctx := context.Background()
+
+	var members []user.User
+	for _, id := range []string{"u1", "u2", "u3", "u4", "u5", "u6"} {
+		members = append(members, user.User{ID: id, Username: id, TeamName: "backend", IsActive: true})
+	}
+	users.UpsertInTeam(ctx, "backend", members)
+	for i := 1; i <= 4; i++ {
+		id := fmt.Sprintf("pr-%d", i)
+		prs.prs[id] = pr.PullRequest{ID: id, AuthorID: "u1", Status: pr.StatusOpen}
+		prs.reviewers[id] = []string{"u2", "u3"}
+	}
+	users.SetActive(ctx, "u2", false)
+	users.SetActive(ctx, "u3", false)
+
+	report, err := svc.RedistributeReviews(ctx, "backend", []string{"u2", "u3"})
+	if err != nil {
+		t.Fatalf("RedistributeReviews: %v", err)
+	}
+	if len(report.Reassigned) != 8 || len(report.WithoutCandidate) != 0 {
+		t.Fatalf("expected 8 reassignments, got %+v", report)
+	}
+	if prs.locks != 1 || prs.writes != 1 || len(prs.assignments) != 4 {
+		t.Fatalf("PRs must be locked and updated in one batch, got %d locks, %d writes, %d assignments",
+			prs.locks, prs.writes, len(prs.assignments))
+	}
+	if events.batches != 1 || len(events.events) != 8 {
+		t.Fatalf("events must be published in one batch, got %d batches with %d events", events.batches, len(events.events))
+	}
+
+	load := map[string]int{}
+	for i := 1; i <= 4; i++ {
+		revs := prs.reviewers[fmt.Sprintf("pr-%d", i)]
+		if len(revs) != 2 || revs[0] == revs[1] {
+			t.Fatalf("pr-%d: unexpected reviewers %v", i, revs)
+		}
+		for _, r := range revs {
+			load[r]++
+		}
+	}
+	for _, id := range []string{"u4", "u5", "u6"} {
+		if load[id] < 2 || load[id] > 3 {
+			t.Fatalf("load is not balanced: %v", load)
+		}
+	}
+}
+
+func TestService_Reassign_ExplicitReviewer(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	events := &eventBusFake{}
+	svc := newTestService(prs, users, newTeamRepoFake(), events, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+		{ID: "u5", Username: "Kate", TeamName: "backend", IsActive: false},
+	})
+	users.UpsertInTeam(ctx, "frontend", []user.User{
+		{ID: "u6", Username: "John", TeamName: "frontend", IsActive: true},
+	})
+	prs.prs["pr-1"] = pr.PullRequest{ID: "pr-1", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-1"] = []string{"u2", "u3"}
+
+	for _, newUserID := range []string{"u1", "u3", "u5", "u6", "missing"} {
+		if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u2", newUserID); !isDomainErr(err, domain.ErrorCodeNoCandidate) {
+			t.Fatalf("new_user_id=%s: want NO_CANDIDATE, got %v", newUserID, err)
+		}
+	}
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u4", "u5"); !isDomainErr(err, domain.ErrorCodeNotAssigned) {
+		t.Fatalf("want NOT_ASSIGNED, got %v", err)
+	}
+
+	p, replacedBy, err := svc.ReassignReviewer(ctx, "pr-1", "u2", "u4")
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	if replacedBy != "u4" || !contains(p.AssignedReviewers, "u4") || contains(p.AssignedReviewers, "u2") {
+		t.Fatalf("explicit reviewer not applied: %s %v", replacedBy, p.AssignedReviewers)
+	}
+}
+
+func TestService_AddRemoveReviewer(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	events := &eventBusFake{}
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, events, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: false},
+		{ID: "u6", Username: "Kate", TeamName: "backend", IsActive: true},
+	})
+	users.UpsertInTeam(ctx, "frontend", []user.User{
+		{ID: "u5", Username: "John", TeamName: "frontend", IsActive: true},
+	})
+	now := time.Now()
+	users.AddUnavailability(ctx, user.Unavailability{UserID: "u6", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	users.AddUnavailability(ctx, user.Unavailability{UserID: "u3", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)})
+	teams.settings["backend"] = team.Settings{MaxReviewers: 2}
+	prs.prs["pr-1"] = pr.PullRequest{ID: "pr-1", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-1"] = []string{"u2"}
+
+	for _, id := range []string{"u1", "u4", "u5", "u6"} {
+		if _, err := svc.AddReviewer(ctx, "pr-1", id); !isDomainErr(err, domain.ErrorCodeNoCandidate) {
+			t.Fatalf("add %s: want NO_CANDIDATE, got %v", id, err)
+		}
+	}
+
+	p, err := svc.AddReviewer(ctx, "pr-1", "u3")
+	if err != nil {
+		t.Fatalf("AddReviewer: %v", err)
+	}
+	if len(p.AssignedReviewers) != 2 || !contains(p.AssignedReviewers, "u3") {
+		t.Fatalf("u3 not added: %v", p.AssignedReviewers)
+	}
+	if _, err := svc.AddReviewer(ctx, "pr-1", "u3"); err != nil {
+		t.Fatalf("adding assigned reviewer must be idempotent, got %v", err)
+	}
+
+	users.SetActive(ctx, "u4", true)
+	if _, err := svc.AddReviewer(ctx, "pr-1", "u4"); !isDomainErr(err, domain.ErrorCodeTooManyReviewers) {
+		t.Fatalf("want TOO_MANY_REVIEWERS, got %v", err)
+	}
+
+	p, err = svc.RemoveReviewer(ctx, "pr-1", "u2")
+	if err != nil {
+		t.Fatalf("RemoveReviewer: %v", err)
+	}
+	if contains(p.AssignedReviewers, "u2") {
+		t.Fatalf("u2 not removed: %v", p.AssignedReviewers)
+	}
+	if _, err := svc.RemoveReviewer(ctx, "pr-1", "u2"); !isDomainErr(err, domain.ErrorCodeNotAssigned) {
+		t.Fatalf("want NOT_ASSIGNED, got %v", err)
+	}
+
+	prs.prs["pr-m"] = pr.PullRequest{ID: "pr-m", Name: "M", AuthorID: "u1", Status: pr.StatusMerged}
+	prs.reviewers["pr-m"] = []string{"u2"}
+	if _, err := svc.AddReviewer(ctx, "pr-m", "u3"); !isDomainErr(err, domain.ErrorCodePRMerged) {
+		t.Fatalf("want PR_MERGED on add, got %v", err)
+	}
+	if _, err := svc.RemoveReviewer(ctx, "pr-m", "u2"); !isDomainErr(err, domain.ErrorCodePRMerged) {
+		t.Fatalf("want PR_MERGED on remove, got %v", err)
+	}
+
+	prs.prs["pr-d"] = pr.PullRequest{ID: "pr-d", Name: "D", AuthorID: "u1", Status: pr.StatusDraft}
+	if _, err := svc.AddReviewer(ctx, "pr-d", "u3"); !isDomainErr(err, domain.ErrorCodePRNotOpen) {
+		t.Fatalf("want PR_NOT_OPEN on add to draft, got %v", err)
+	}
+	if len(prs.reviewers["pr-d"]) != 0 {
+		t.Fatalf("draft must stay without reviewers, got %v", prs.reviewers["pr-d"])
+	}
+
+	var types []string
+	for _, e := range events.events {
+		types = append(types, e.EventType())
+	}
+	if len(types) != 2 || types[0] != "pr.reviewer_added" || types[1] != "pr.reviewer_removed" {
+		t.Fatalf("unexpected events %v", types)
+	}
+}
+
+func TestService_DeclineReview(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	events := &eventBusFake{}
+	svc := newTestService(prs, users, newTeamRepoFake(), events, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+	})
+	prs.prs["pr-1"] = pr.PullRequest{ID: "pr-1", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-1"] = []string{"u2", "u3"}
+
+	if _, _, err := svc.DeclineReview(ctx, "pr-1", "u2", " "); !isDomainErr(err, domain.ErrorCodeBadRequest) {
+		t.Fatalf("want BAD_REQUEST without reason, got %v", err)
+	}
+	if _, _, err := svc.DeclineReview(ctx, "pr-1", "u4", "busy"); !isDomainErr(err, domain.ErrorCodeNotAssigned) {
+		t.Fatalf("want NOT_ASSIGNED, got %v", err)
+	}
+
+	p, replacedBy, err := svc.DeclineReview(ctx, "pr-1", "u2", "busy")
+	if err != nil {
+		t.Fatalf("DeclineReview: %v", err)
+	}
+	if replacedBy != "u4" || contains(p.AssignedReviewers, "u2") {
+		t.Fatalf("unexpected replacement %s, reviewers %v", replacedBy, p.AssignedReviewers)
+	}
+	if len(prs.declines) != 1 || prs.declines[0] != (pr.Decline{PRID: "pr-1", UserID: "u2", ReplacedBy: "u4", Reason: "busy"}) {
+		t.Fatalf("decline not recorded: %+v", prs.declines)
+	}
+
+	p, replacedBy, err = svc.DeclineReview(ctx, "pr-1", "u3", "busy")
+	if err != nil {
+		t.Fatalf("decline without candidate (u2 already declined) must succeed: %v", err)
+	}
+	if replacedBy != "" || contains(p.AssignedReviewers, "u3") || len(p.AssignedReviewers) != 1 {
+		t.Fatalf("u3 must be dropped without replacement, got %s, reviewers %v", replacedBy, p.AssignedReviewers)
+	}
+	if p.Staffing == nil || p.Staffing.Requested != 2 || p.Staffing.Assigned != 1 {
+		t.Fatalf("PR must be reported as under-staffed, got %+v", p.Staffing)
+	}
+	if len(prs.declines) != 2 || prs.declines[1] != (pr.Decline{PRID: "pr-1", UserID: "u3", Reason: "busy"}) {
+		t.Fatalf("decline without replacement not recorded: %+v", prs.declines)
+	}
+	last, ok := events.events[len(events.events)-1].(domain.ReviewDeclined)
+	if !ok || last.NewReviewerID != "" {
+		t.Fatalf("expected pr.review_declined event without new reviewer, got %+v", events.events[len(events.events)-1])
+	}
+}
+
+func TestService_Create_SkipsUnavailableReviewers(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	svc := newTestService(prs, users, newTeamRepoFake(), &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+	})
+	now := time.Now()
+	users.AddUnavailability(ctx, user.Unavailability{UserID: "u2", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	users.AddUnavailability(ctx, user.Unavailability{UserID: "u3", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)})
+
+	p, err := svc.Create(ctx, pr.CreateParams{ID: "pr-1", Name: "X", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if contains(p.AssignedReviewers, "u2") || len(p.AssignedReviewers) != 2 {
+		t.Fatalf("user on vacation must be skipped, got %v", p.AssignedReviewers)
+	}
+}
+
+func TestService_ReviewCapacity(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	svc := newTestService(prs, users, newTeamRepoFake(), &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true, MaxOpenReviews: 1},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true, MaxOpenReviews: 1},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+	})
+
+	first, err := svc.Create(ctx, pr.CreateParams{ID: "pr-1", Name: "X", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !contains(first.AssignedReviewers, "u2") || !contains(first.AssignedReviewers, "u3") || first.Staffing != nil {
+		t.Fatalf("expected fully staffed PR, got %v %+v", first.AssignedReviewers, first.Staffing)
+	}
+
+	second, err := svc.Create(ctx, pr.CreateParams{ID: "pr-2", Name: "Y", AuthorID: "u4"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if second.Staffing == nil || second.Staffing.Assigned != len(second.AssignedReviewers) || len(second.Staffing.AtCapacity) == 0 {
+		t.Fatalf("expected partial staffing note, got %v %+v", second.AssignedReviewers, second.Staffing)
+	}
+	for _, id := range second.Staffing.AtCapacity {
+		if contains(second.AssignedReviewers, id) {
+			t.Fatalf("user %s at capacity must not be assigned", id)
+		}
+	}
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-2", second.AssignedReviewers[0], "u2"); err == nil {
+		t.Fatalf("expected NO_CANDIDATE for user at capacity")
+	} else if de, ok := err.(*domain.DomainError); !ok || de.Code != domain.ErrorCodeNoCandidate {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.AddReviewer(ctx, "pr-2", "u3"); err == nil {
+		t.Fatalf("expected NO_CANDIDATE when adding user at capacity")
+	}
+}
+
+func TestService_Create_PrefersCodeOwners(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true, Expertise: []string{"Security"}},
+		{ID: "u5", Username: "Kim", TeamName: "backend", IsActive: true},
+	})
+	teams.SetOwnershipRules(ctx, "backend", []team.OwnershipRule{
+		{Pattern: "*.go", Owners: []string{"u3"}},
+		{Pattern: "/migrations/", Owners: []string{"u5"}},
+	})
+
+	p, err := svc.Create(ctx, pr.CreateParams{ID: "pr-1", Name: "X", AuthorID: "u1", Files: []string{"migrations/11.sql"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !contains(p.AssignedReviewers, "u5") || len(p.AssignedReviewers) != 2 {
+		t.Fatalf("expected code owner u5 among reviewers, got %v", p.AssignedReviewers)
+	}
+	if len(p.Files) != 1 {
+		t.Fatalf("files must be stored on PR, got %v", p.Files)
+	}
+
+	p, err = svc.Create(ctx, pr.CreateParams{ID: "pr-2", Name: "Y", AuthorID: "u1", Labels: []string{"security"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !contains(p.AssignedReviewers, "u4") {
+		t.Fatalf("expected expert u4 among reviewers, got %v", p.AssignedReviewers)
+	}
+
+	p, err = svc.Create(ctx, pr.CreateParams{ID: "pr-3", Name: "Z", AuthorID: "u3", Files: []string{"main.go"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(p.AssignedReviewers) != 2 {
+		t.Fatalf("author-owned files must fall back to regular selection, got %v", p.AssignedReviewers)
+	}
+}
+
+func TestService_Create_FillsFromPartnerTeams(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+	})
+	users.UpsertInTeam(ctx, "platform", []user.User{
+		{ID: "p1", Username: "Kim", TeamName: "platform", IsActive: true},
+		{ID: "p2", Username: "Lee", TeamName: "platform", IsActive: true},
+	})
+
+	p, err := svc.Create(ctx, pr.CreateParams{ID: "pr-1", Name: "X", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(p.AssignedReviewers) != 1 || p.Staffing == nil {
+		t.Fatalf("without partners only home reviewer expected, got %v", p.AssignedReviewers)
+	}
+
+	settings := team.DefaultSettings()
+	settings.PartnerTeams = []string{"platform"}
+	teams.settings["backend"] = settings
+
+	p, err = svc.Create(ctx, pr.CreateParams{ID: "pr-2", Name: "Y", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(p.AssignedReviewers) != 2 || p.AssignedReviewers[0] != "u2" || !strings.HasPrefix(p.AssignedReviewers[1], "p") {
+		t.Fatalf("expected home reviewer first and one partner reviewer, got %v", p.AssignedReviewers)
+	}
+	if p.Staffing != nil {
+		t.Fatalf("PR must be fully staffed, got %+v", p.Staffing)
+	}
+
+	if _, err := svc.AddReviewer(ctx, "pr-1", "p2"); err != nil {
+		t.Fatalf("partner team member must be addable: %v", err)
+	}
+}
+
+func TestService_ReplacementFallsBackToPartnerTeams(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+	})
+	users.UpsertInTeam(ctx, "platform", []user.User{
+		{ID: "p1", Username: "Kim", TeamName: "platform", IsActive: true},
+		{ID: "p2", Username: "Lee", TeamName: "platform", IsActive: true},
+		{ID: "p3", Username: "Max", TeamName: "platform", IsActive: true},
+	})
+	prs.prs["pr-1"] = pr.PullRequest{ID: "pr-1", Name: "X", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-1"] = []string{"u2", "u3"}
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u2", ""); !isDomainErr(err, domain.ErrorCodeNoCandidate) {
+		t.Fatalf("without partners want NO_CANDIDATE, got %v", err)
+	}
+
+	settings := team.DefaultSettings()
+	settings.PartnerTeams = []string{"platform"}
+	teams.settings["backend"] = settings
+
+	_, replacedBy, err := svc.ReassignReviewer(ctx, "pr-1", "u2", "")
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	if !strings.HasPrefix(replacedBy, "p") {
+		t.Fatalf("expected partner reviewer, got %s", replacedBy)
+	}
+
+	prs.prs["pr-2"] = pr.PullRequest{ID: "pr-2", Name: "Y", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-2"] = []string{"u2", "u3"}
+	_, replacedBy, err = svc.DeclineReview(ctx, "pr-2", "u3", "busy")
+	if err != nil {
+		t.Fatalf("DeclineReview: %v", err)
+	}
+	if !strings.HasPrefix(replacedBy, "p") {
+		t.Fatalf("expected partner reviewer on decline, got %s", replacedBy)
+	}
+
+	prs.prs["pr-3"] = pr.PullRequest{ID: "pr-3", Name: "Z", AuthorID: "u1", Status: pr.StatusOpen}
+	prs.reviewers["pr-3"] = []string{"u2", "u3"}
+	if _, err := users.SetActive(ctx, "u2", false); err != nil {
+		t.Fatalf("SetActive: %v", err)
+	}
+	report, err := svc.ReassignOpenReviews(ctx, "u2")
+	if err != nil {
+		t.Fatalf("ReassignOpenReviews: %v", err)
+	}
+	if len(report.Reassigned) != 2 || report.Reassigned[1].PRID != "pr-3" || !strings.HasPrefix(report.Reassigned[1].ReplacedBy, "p") {
+		t.Fatalf("expected deactivation to reassign to a partner reviewer, got %+v", report)
+	}
+}
+
+func TestService_MentorPairing(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true, Seniority: user.SeniorityMiddle},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true, Seniority: user.SeniorityMiddle},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true, Seniority: user.SenioritySenior},
+		{ID: "u5", Username: "Kim", TeamName: "backend", IsActive: true, Seniority: user.SeniorityJunior},
+		{ID: "u6", Username: "Lee", TeamName: "backend", IsActive: true, Seniority: user.SenioritySenior},
+		{ID: "u7", Username: "Max", TeamName: "backend", IsActive: true, Seniority: user.SeniorityJunior},
+	})
+
+	p, err := svc.Create(ctx, pr.CreateParams{ID: "pr-1", Name: "X", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if contains(p.AssignedReviewers, "u4") || contains(p.AssignedReviewers, "u5") {
+		t.Fatalf("pairing is disabled by default, got %v", p.AssignedReviewers)
+	}
+
+	settings := team.DefaultSettings()
+	settings.MentorPairing = true
+	teams.settings["backend"] = settings
+
+	p, err = svc.Create(ctx, pr.CreateParams{ID: "pr-2", Name: "Y", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(p.AssignedReviewers) != 2 || p.AssignedReviewers[0] != "u4" || p.AssignedReviewers[1] != "u5" {
+		t.Fatalf("expected one senior and one junior, got %v", p.AssignedReviewers)
+	}
+
+	p, replacedBy, err := svc.ReassignReviewer(ctx, "pr-2", "u4", "")
+	if err != nil {
+		t.Fatalf("Reassign senior: %v", err)
+	}
+	if replacedBy != "u6" {
+		t.Fatalf("senior must be replaced by a senior, got %s", replacedBy)
+	}
+
+	_, replacedBy, err = svc.ReassignReviewer(ctx, "pr-2", "u5", "")
+	if err != nil {
+		t.Fatalf("Reassign junior: %v", err)
+	}
+	if replacedBy != "u7" {
+		t.Fatalf("junior must be replaced by a junior, got %s (reviewers %v)", replacedBy, p.AssignedReviewers)
+	}
+}
+
+func TestService_PairRules(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+		{ID: "u5", Username: "Kim", TeamName: "backend", IsActive: true},
+	})
+	teams.AddPairRule(ctx, team.PairRule{TeamName: "backend", ReviewerID: "u2", AuthorID: "u1", Kind: team.PairRuleExclude})
+	teams.AddPairRule(ctx, team.PairRule{TeamName: "backend", ReviewerID: "u4", AuthorID: "u1", Kind: team.PairRulePrefer})
+
+	p, err := svc.Create(ctx, pr.CreateParams{ID: "pr-1", Name: "X", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(p.AssignedReviewers) != 2 || p.AssignedReviewers[0] != "u4" || contains(p.AssignedReviewers, "u2") {
+		t.Fatalf("expected preferred u4 and no excluded u2, got %v", p.AssignedReviewers)
+	}
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u3", "u2"); !isDomainErr(err, domain.ErrorCodeNoCandidate) {
+		t.Fatalf("excluded reviewer must not be an eligible replacement, got %v", err)
+	}
+	if _, err := svc.AddReviewer(ctx, "pr-1", "u2"); !isDomainErr(err, domain.ErrorCodeNoCandidate) {
+		t.Fatalf("excluded reviewer must not be addable, got %v", err)
+	}
+
+	p, err = svc.Create(ctx, pr.CreateParams{ID: "pr-2", Name: "Y", AuthorID: "u5"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if p.AssignedReviewers[0] != "u1" || p.AssignedReviewers[1] != "u2" {
+		t.Fatalf("rules of other authors must not apply, got %v", p.AssignedReviewers)
+	}
+
+	_, replacedBy, err := svc.ReassignReviewer(ctx, "pr-1", "u4", "")
+	if err != nil {
+		t.Fatalf("Reassign: %v", err)
+	}
+	if replacedBy != "u5" {
+		t.Fatalf("excluded reviewer must be skipped on reassign, got %s", replacedBy)
+	}
+}
+
+func TestService_RedistributeReviews_PairRules(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+		{ID: "u5", Username: "Kim", TeamName: "backend", IsActive: true},
+	})
+	for _, id := range []string{"pr-1", "pr-2"} {
+		prs.prs[id] = pr.PullRequest{ID: id, AuthorID: "u1", Status: pr.StatusOpen}
+		prs.reviewers[id] = []string{"u2", "u3"}
+	}
+	teams.AddPairRule(ctx, team.PairRule{TeamName: "backend", ReviewerID: "u5", AuthorID: "u1", Kind: team.PairRulePrefer})
+
+	report, err := svc.RedistributeReviews(ctx, "backend", []string{"u2"})
+	if err != nil {
+		t.Fatalf("RedistributeReviews: %v", err)
+	}
+	if len(report.Reassigned) != 2 {
+		t.Fatalf("expected 2 reassignments, got %+v", report)
+	}
+	for _, r := range report.Reassigned {
+		if r.ReplacedBy != "u5" {
+			t.Fatalf("preferred reviewer must be picked first even if busier, got %+v", report.Reassigned)
+		}
+	}
+}
+
+func TestService_ExplainAssignment(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, newSeededRand(1))
+	ctx := context.Background()
+
+	members := []user.User{{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}}
+	for i := 2; i <= 8; i++ {
+		members = append(members, user.User{ID: fmt.Sprintf("u%d", i), Username: fmt.Sprintf("User%d", i), TeamName: "backend", IsActive: true, ReviewWeight: i})
+	}
+	users.UpsertInTeam(ctx, "backend", members)
+
+	for _, strategy := range []pr.Strategy{pr.StrategyRandom, pr.StrategyWeighted, pr.StrategyRoundRobin} {
+		settings := team.DefaultSettings()
+		settings.ReviewerStrategy = string(strategy)
+		teams.settings["backend"] = settings
+
+		id := "pr-" + string(strategy)
+		created, err := svc.Create(ctx, pr.CreateParams{ID: id, Name: "X", AuthorID: "u1"})
+		if err != nil {
+			t.Fatalf("%s: Create: %v", strategy, err)
+		}
+		if _, _, err := svc.ReassignReviewer(ctx, id, created.AssignedReviewers[0], ""); err != nil {
+			t.Fatalf("%s: Reassign: %v", strategy, err)
+		}
+
+		locks := prs.locks
+		assignments, err := svc.ExplainAssignment(ctx, id)
+		if err != nil {
+			t.Fatalf("%s: ExplainAssignment: %v", strategy, err)
+		}
+		if prs.locks != locks {
+			t.Fatalf("%s: ExplainAssignment must not lock the pull request", strategy)
+		}
+		if len(assignments) != 2 || assignments[0].Operation != "create" || assignments[1].Operation != "reassign" {
+			t.Fatalf("%s: unexpected assignments %+v", strategy, assignments)
+		}
+
+		d := assignments[0].Decisions[0]
+		if d.Strategy != strategy || d.Purpose != "strategy" || len(d.Candidates) != 7 {
+			t.Fatalf("%s: unexpected decision %+v", strategy, d)
+		}
+		if !slices.Equal(d.Selected, created.AssignedReviewers) {
+			t.Fatalf("%s: recorded %v, assigned %v", strategy, d.Selected, created.AssignedReviewers)
+		}
+		for _, a := range assignments {
+			for _, d := range a.Decisions {
+				if !d.Reproducible {
+					t.Fatalf("%s: %s decision not reproducible: selected %v, replayed %v", strategy, a.Operation, d.Selected, d.Replayed)
+				}
+			}
+		}
+	}
+
+	if _, err := svc.ExplainAssignment(ctx, "missing"); !isDomainErr(err, domain.ErrorCodeNotFound) {
+		t.Fatalf("want NOT_FOUND, got %v", err)
+	}
+}
+
+func TestService_ExplainAssignment_UnseedableSource(t *testing.T) {
+	users := newUserRepoFake()
+	prs := newPRRepoFake()
+	teams := newTeamRepoFake()
+	svc := newTestService(prs, users, teams, &eventBusFake{}, fixedRand{})
+	ctx := context.Background()
+
+	users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Eve", TeamName: "backend", IsActive: true},
+		{ID: "u4", Username: "Tom", TeamName: "backend", IsActive: true},
+	})
+
+	for _, c := range []struct {
+		strategy     pr.Strategy
+		reproducible bool
+	}{
+		{pr.StrategyRandom, false},
+		{pr.StrategyWeighted, false},
+		{pr.StrategyRoundRobin, true},
+	} {
+		settings := team.DefaultSettings()
+		settings.ReviewerStrategy = string(c.strategy)
+		teams.settings["backend"] = settings
+
+		id := "pr-" + string(c.strategy)
+		if _, err := svc.Create(ctx, pr.CreateParams{ID: id, Name: "X", AuthorID: "u1"}); err != nil {
+			t.Fatalf("%s: Create: %v", c.strategy, err)
+		}
+		assignments, err := svc.ExplainAssignment(ctx, id)
+		if err != nil {
+			t.Fatalf("%s: ExplainAssignment: %v", c.strategy, err)
+		}
+		d := assignments[0].Decisions[0]
+		if d.Seed != 0 {
+			t.Fatalf("%s: seed must come from the injected source, got %d", c.strategy, d.Seed)
+		}
+		if d.Reproducible != c.reproducible {
+			t.Fatalf("%s: expected reproducible=%v, got %+v", c.strategy, c.reproducible, d)
+		}
 	}
-	return false
 }
@@ -2,4 +2,17 @@ package domain
 
 type RandomSource interface {
 	Shuffle(n int, swap func(i, j int))
+	Intn(n int) int
+}
+
+type SeedableSource interface {
+	RandomSource
+	WithSeed(seed int64) RandomSource
+}
+
+func Reseed(r RandomSource, seed int64) RandomSource {
+	if s, ok := r.(SeedableSource); ok {
+		return s.WithSeed(seed)
+	}
+	return r
 }
@@ -1,13 +1,24 @@
 package stats
 
+import "time"
+
 type UserAssignmentStat struct {
 	UserID         string
 	AssignedTotal  int
 	AssignedOpen   int
 	AssignedMerged int
+	Declined       int
 }
 
 type PRAssignmentStat struct {
 	PullRequestID string
 	ReviewerCount int
 }
+
+type ReviewDecline struct {
+	PullRequestID string
+	UserID        string
+	ReplacedBy    string
+	Reason        string
+	DeclinedAt    time.Time
+}
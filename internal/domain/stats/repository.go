@@ -5,4 +5,5 @@ import "context"
 type Repository interface {
 	GetUserAssignmentStats(ctx context.Context, teamName *string) ([]UserAssignmentStat, error)
 	GetPRAssignmentStats(ctx context.Context) ([]PRAssignmentStat, error)
+	GetReviewDeclines(ctx context.Context, teamName *string) ([]ReviewDecline, error)
 }
@@ -5,6 +5,7 @@ import "context"
 type Service interface {
 	GetUserStats(ctx context.Context, teamName *string) ([]UserAssignmentStat, error)
 	GetPRStats(ctx context.Context) ([]PRAssignmentStat, error)
+	GetDeclines(ctx context.Context, teamName *string) ([]ReviewDecline, error)
 }
 
 type service struct {
@@ -22,3 +23,7 @@ func (s *service) GetUserStats(ctx context.Context, teamName *string) ([]UserAss
 func (s *service) GetPRStats(ctx context.Context) ([]PRAssignmentStat, error) {
 	return s.repo.GetPRAssignmentStats(ctx)
 }
+
+func (s *service) GetDeclines(ctx context.Context, teamName *string) ([]ReviewDecline, error) {
+	return s.repo.GetReviewDeclines(ctx, teamName)
+}
@@ -8,8 +8,9 @@ import (
 )
 
 type repoFake struct {
-	users []stats.UserAssignmentStat
-	prs   []stats.PRAssignmentStat
+	users    []stats.UserAssignmentStat
+	prs      []stats.PRAssignmentStat
+	declines []stats.ReviewDecline
 }
 
 func (r *repoFake) GetUserAssignmentStats(ctx context.Context, teamName *string) ([]stats.UserAssignmentStat, error) {
@@ -18,6 +19,9 @@ func (r *repoFake) GetUserAssignmentStats(ctx context.Context, teamName *string)
 func (r *repoFake) GetPRAssignmentStats(ctx context.Context) ([]stats.PRAssignmentStat, error) {
 	return append([]stats.PRAssignmentStat(nil), r.prs...), nil
 }
+func (r *repoFake) GetReviewDeclines(ctx context.Context, teamName *string) ([]stats.ReviewDecline, error) {
+	return append([]stats.ReviewDecline(nil), r.declines...), nil
+}
 
 func TestStatsService_PassThrough(t *testing.T) {
 	r := &repoFake{
@@ -27,6 +31,9 @@ func TestStatsService_PassThrough(t *testing.T) {
 		prs: []stats.PRAssignmentStat{
 			{PullRequestID: "pr-1", ReviewerCount: 2},
 		},
+		declines: []stats.ReviewDecline{
+			{PullRequestID: "pr-1", UserID: "u2", ReplacedBy: "u3", Reason: "no context"},
+		},
 	}
 	svc := stats.NewService(r)
 
@@ -39,4 +46,9 @@ func TestStatsService_PassThrough(t *testing.T) {
 	if err != nil || len(ps) != 1 || ps[0].PullRequestID != "pr-1" {
 		t.Fatalf("unexpected pr stats: %v %v", ps, err)
 	}
+
+	ds, err := svc.GetDeclines(context.Background(), nil)
+	if err != nil || len(ds) != 1 || ds[0].Reason != "no context" {
+		t.Fatalf("unexpected declines: %v %v", ds, err)
+	}
 }
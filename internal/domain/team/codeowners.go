@@ -0,0 +1,78 @@
+package team
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CodeownersEntry struct {
+	Line    int
+	Pattern string
+	Handles []string
+}
+
+type UnknownReason string
+
+const (
+	UnknownNotFound  UnknownReason = "not_found"
+	UnknownAmbiguous UnknownReason = "ambiguous"
+	UnknownEmail     UnknownReason = "email"
+)
+
+type UnknownOwner struct {
+	Line   int
+	Handle string
+	Reason UnknownReason
+}
+
+type CodeownersImport struct {
+	Rules   []OwnershipRule
+	Unknown []UnknownOwner
+}
+
+type PathOwners struct {
+	Path    string
+	Pattern string
+	Owners  []string
+}
+
+func ParseCodeowners(content string) ([]CodeownersEntry, error) {
+	var entries []CodeownersEntry
+	for i, raw := range strings.Split(content, "\n") {
+		line := i + 1
+		fields := strings.Fields(stripComment(raw))
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		if strings.HasPrefix(pattern, "!") {
+			return nil, fmt.Errorf("line %d: negated patterns are not supported", line)
+		}
+		if strings.ContainsAny(pattern, "[]") {
+			return nil, fmt.Errorf("line %d: character ranges are not supported", line)
+		}
+		if err := ValidatePattern(pattern); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		entries = append(entries, CodeownersEntry{Line: line, Pattern: pattern, Handles: fields[1:]})
+	}
+	return entries, nil
+}
+
+func stripComment(line string) string {
+	line = strings.TrimRight(line, "\r")
+	for i := 0; i < len(line); i++ {
+		if line[i] != '#' {
+			continue
+		}
+		if i > 0 && line[i-1] == '\\' {
+			continue
+		}
+		if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+			return line[:i]
+		}
+	}
+	return line
+}
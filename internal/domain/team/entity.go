@@ -1,12 +1,105 @@
 package team
 
+import "prservice/internal/domain/user"
+
+const DefaultMaxReviewers = 2
+
 type Member struct {
-	ID       string
-	Username string
-	IsActive bool
+	ID             string
+	Username       string
+	IsActive       bool
+	ReviewWeight   int
+	MaxOpenReviews int
+	Expertise      []string
+	Seniority      user.Seniority
+}
+
+type Settings struct {
+	ReviewerStrategy     string
+	MinReviewers         int
+	MaxReviewers         int
+	RequiredApprovals    int
+	ReassignOnDeactivate bool
+	PartnerTeams         []string
+	MentorPairing        bool
+	RotationWindow       int
+}
+
+type SettingsUpdate struct {
+	ReviewerStrategy     *string
+	MinReviewers         *int
+	MaxReviewers         *int
+	RequiredApprovals    *int
+	ReassignOnDeactivate *bool
+	PartnerTeams         *[]string
+	MentorPairing        *bool
+	RotationWindow       *int
 }
 
 type Team struct {
-	Name    string
-	Members []Member
+	Name     string
+	Members  []Member
+	Settings Settings
+}
+
+func DefaultSettings() Settings {
+	return Settings{MaxReviewers: DefaultMaxReviewers}
+}
+
+func (s Settings) Apply(u SettingsUpdate) Settings {
+	if u.ReviewerStrategy != nil {
+		s.ReviewerStrategy = *u.ReviewerStrategy
+	}
+	if u.MinReviewers != nil {
+		s.MinReviewers = *u.MinReviewers
+	}
+	if u.MaxReviewers != nil {
+		s.MaxReviewers = *u.MaxReviewers
+	}
+	if u.RequiredApprovals != nil {
+		s.RequiredApprovals = *u.RequiredApprovals
+	}
+	if u.ReassignOnDeactivate != nil {
+		s.ReassignOnDeactivate = *u.ReassignOnDeactivate
+	}
+	if u.MentorPairing != nil {
+		s.MentorPairing = *u.MentorPairing
+	}
+	if u.RotationWindow != nil {
+		s.RotationWindow = *u.RotationWindow
+	}
+	if u.PartnerTeams != nil {
+		s.PartnerTeams = append([]string{}, (*u.PartnerTeams)...)
+	}
+	return s
+}
+
+type OwnershipRule struct {
+	Pattern string
+	Owners  []string
+}
+
+type PairRuleKind string
+
+const (
+	PairRuleExclude PairRuleKind = "exclude"
+	PairRulePrefer  PairRuleKind = "prefer"
+)
+
+func (k PairRuleKind) Valid() bool {
+	return k == PairRuleExclude || k == PairRulePrefer
+}
+
+type PairRule struct {
+	ID         int64
+	TeamName   string
+	ReviewerID string
+	AuthorID   string
+	Kind       PairRuleKind
+	Reason     string
+}
+
+type DeactivationResult struct {
+	Users        []user.User
+	Reassignment user.ReassignReport
 }
@@ -0,0 +1,101 @@
+package team
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+type Ownership struct {
+	rules    []OwnershipRule
+	patterns []*regexp.Regexp
+}
+
+func NewOwnership(rules []OwnershipRule) Ownership {
+	o := Ownership{rules: rules, patterns: make([]*regexp.Regexp, len(rules))}
+	for i, r := range rules {
+		o.patterns[i], _ = compilePattern(r.Pattern)
+	}
+	return o
+}
+
+func (o Ownership) Match(path string) (OwnershipRule, bool) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "/")
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if o.patterns[i] != nil && o.patterns[i].MatchString(path) {
+			return o.rules[i], true
+		}
+	}
+	return OwnershipRule{}, false
+}
+
+func (o Ownership) Owners(paths []string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, p := range paths {
+		rule, ok := o.Match(p)
+		if !ok {
+			continue
+		}
+		for _, owner := range rule.Owners {
+			if !seen[owner] {
+				seen[owner] = true
+				res = append(res, owner)
+			}
+		}
+	}
+	return res
+}
+
+func ValidatePattern(pattern string) error {
+	_, err := compilePattern(pattern)
+	return err
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" {
+		return nil, errors.New("empty pattern")
+	}
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return regexp.Compile(`^.*$`)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*") && !strings.HasSuffix(p, "/**"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
@@ -4,6 +4,13 @@ import "context"
 
 type Repository interface {
 	Exists(ctx context.Context, name string) (bool, error)
-	Create(ctx context.Context, name string) error
+	Create(ctx context.Context, name string, settings Settings) error
 	GetWithMembers(ctx context.Context, name string) (Team, error)
+	GetSettings(ctx context.Context, name string) (Settings, error)
+	UpdateSettings(ctx context.Context, name string, settings Settings) error
+	GetOwnershipRules(ctx context.Context, name string) ([]OwnershipRule, error)
+	SetOwnershipRules(ctx context.Context, name string, rules []OwnershipRule) error
+	AddPairRule(ctx context.Context, rule PairRule) (PairRule, error)
+	ListPairRules(ctx context.Context, name string) ([]PairRule, error)
+	DeletePairRule(ctx context.Context, name string, id int64) error
 }
@@ -2,7 +2,10 @@ package team
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"prservice/internal/domain"
 	"prservice/internal/domain/user"
@@ -11,26 +14,43 @@ import (
 type Service interface {
 	AddTeam(ctx context.Context, team Team) (Team, error)
 	GetTeam(ctx context.Context, name string) (Team, error)
+	UpdateSettings(ctx context.Context, name string, update SettingsUpdate) (Team, error)
+	ReassignOnDeactivate(ctx context.Context, name string) (bool, error)
+	DeactivateUsers(ctx context.Context, name string, userIDs []string) (DeactivationResult, error)
+	GetOwnershipRules(ctx context.Context, name string) ([]OwnershipRule, error)
+	SetOwnershipRules(ctx context.Context, name string, rules []OwnershipRule) ([]OwnershipRule, error)
+	ImportCodeowners(ctx context.Context, name, content string) (CodeownersImport, error)
+	LookupOwners(ctx context.Context, name string, paths []string) ([]PathOwners, error)
+	AddPairRule(ctx context.Context, rule PairRule) (PairRule, error)
+	ListPairRules(ctx context.Context, name string) ([]PairRule, error)
+	RemovePairRule(ctx context.Context, name string, id int64) error
+}
+
+type ReviewRedistributor interface {
+	RedistributeReviews(ctx context.Context, teamName string, userIDs []string) (user.ReassignReport, error)
 }
 
 type service struct {
-	uow    domain.UnitOfWork
-	teams  Repository
-	users  user.Repository
-	events domain.EventBus
+	uow     domain.UnitOfWork
+	teams   Repository
+	users   user.Repository
+	reviews ReviewRedistributor
+	events  domain.EventPublisher
 }
 
 func NewService(
 	uow domain.UnitOfWork,
 	teams Repository,
 	users user.Repository,
-	events domain.EventBus,
+	reviews ReviewRedistributor,
+	events domain.EventPublisher,
 ) Service {
 	return &service{
-		uow:    uow,
-		teams:  teams,
-		users:  users,
-		events: events,
+		uow:     uow,
+		teams:   teams,
+		users:   users,
+		reviews: reviews,
+		events:  events,
 	}
 }
 
@@ -50,17 +70,33 @@ func (s *service) AddTeam(ctx context.Context, t Team) (Team, error) {
 			}
 		}
 
-		if err := s.teams.Create(ctx, t.Name); err != nil {
+		if t.Settings.MaxReviewers == 0 {
+			t.Settings.MaxReviewers = DefaultMaxReviewers
+		}
+		if err := validateSettings(t.Settings); err != nil {
+			return err
+		}
+		if t.Settings.PartnerTeams, err = s.validatePartners(ctx, t.Name, t.Settings.PartnerTeams); err != nil {
+			return err
+		}
+
+		if err := s.teams.Create(ctx, t.Name, t.Settings); err != nil {
 			return err
 		}
 
 		users := make([]user.User, 0, len(t.Members))
+		memberIDs := make([]string, 0, len(t.Members))
 		for _, m := range t.Members {
+			memberIDs = append(memberIDs, m.ID)
 			users = append(users, user.User{
-				ID:       m.ID,
-				Username: m.Username,
-				TeamName: t.Name,
-				IsActive: m.IsActive,
+				ID:             m.ID,
+				Username:       m.Username,
+				TeamName:       t.Name,
+				IsActive:       m.IsActive,
+				ReviewWeight:   m.ReviewWeight,
+				MaxOpenReviews: m.MaxOpenReviews,
+				Expertise:      m.Expertise,
+				Seniority:      m.Seniority,
 			})
 		}
 
@@ -71,13 +107,12 @@ func (s *service) AddTeam(ctx context.Context, t Team) (Team, error) {
 		result = t
 
 		if s.events != nil {
-			s.events.Publish(ctx, domain.Event{
-				Type: "team.created",
-				Payload: map[string]any{
-					"team_name": t.Name,
-					"members":   len(t.Members),
-				},
-			})
+			if err := s.events.Publish(ctx, domain.TeamCreated{
+				TeamName:  t.Name,
+				MemberIDs: memberIDs,
+			}); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -88,3 +123,503 @@ func (s *service) AddTeam(ctx context.Context, t Team) (Team, error) {
 func (s *service) GetTeam(ctx context.Context, name string) (Team, error) {
 	return s.teams.GetWithMembers(ctx, name)
 }
+
+func (s *service) UpdateSettings(ctx context.Context, name string, update SettingsUpdate) (Team, error) {
+	var result Team
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.teams.GetSettings(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		settings := current.Apply(update)
+		if err := validateSettings(settings); err != nil {
+			return err
+		}
+		if settings.PartnerTeams, err = s.validatePartners(ctx, name, settings.PartnerTeams); err != nil {
+			return err
+		}
+
+		if err := s.teams.UpdateSettings(ctx, name, settings); err != nil {
+			return err
+		}
+
+		result, err = s.teams.GetWithMembers(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.TeamUpdated{
+				TeamName:             name,
+				ReviewerStrategy:     settings.ReviewerStrategy,
+				MinReviewers:         settings.MinReviewers,
+				MaxReviewers:         settings.MaxReviewers,
+				RequiredApprovals:    settings.RequiredApprovals,
+				ReassignOnDeactivate: settings.ReassignOnDeactivate,
+				PartnerTeams:         append([]string{}, settings.PartnerTeams...),
+				MentorPairing:        settings.MentorPairing,
+				RotationWindow:       settings.RotationWindow,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+func (s *service) ReassignOnDeactivate(ctx context.Context, name string) (bool, error) {
+	if name == "" {
+		return false, nil
+	}
+	settings, err := s.teams.GetSettings(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return settings.ReassignOnDeactivate, nil
+}
+
+func (s *service) DeactivateUsers(ctx context.Context, name string, userIDs []string) (DeactivationResult, error) {
+	var result DeactivationResult
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ensureExists(ctx, name); err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool, len(userIDs))
+		ids := make([]string, 0, len(userIDs))
+		for _, id := range userIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			u, err := s.users.SetActive(ctx, id, false)
+			if err != nil {
+				return err
+			}
+			if u.TeamName != name {
+				return &domain.DomainError{
+					Code:       domain.ErrorCodeBadRequest,
+					Message:    "user " + id + " is not a member of team " + name,
+					HTTPStatus: http.StatusBadRequest,
+				}
+			}
+			ids = append(ids, id)
+			result.Users = append(result.Users, u)
+		}
+
+		if s.reviews != nil {
+			report, err := s.reviews.RedistributeReviews(ctx, name, ids)
+			if err != nil {
+				return err
+			}
+			result.Reassignment = report
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.UsersDeactivated{
+				TeamName:         name,
+				UserIDs:          ids,
+				Reassigned:       len(result.Reassignment.Reassigned),
+				WithoutCandidate: len(result.Reassignment.WithoutCandidate),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+func (s *service) GetOwnershipRules(ctx context.Context, name string) ([]OwnershipRule, error) {
+	if err := s.ensureExists(ctx, name); err != nil {
+		return nil, err
+	}
+	return s.teams.GetOwnershipRules(ctx, name)
+}
+
+func (s *service) SetOwnershipRules(ctx context.Context, name string, rules []OwnershipRule) ([]OwnershipRule, error) {
+	var result []OwnershipRule
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ensureExists(ctx, name); err != nil {
+			return err
+		}
+
+		normalized := make([]OwnershipRule, 0, len(rules))
+		for _, r := range rules {
+			pattern := strings.TrimSpace(r.Pattern)
+			if err := ValidatePattern(pattern); err != nil {
+				return &domain.DomainError{
+					Code:       domain.ErrorCodeBadRequest,
+					Message:    fmt.Sprintf("invalid pattern %q: %v", r.Pattern, err),
+					HTTPStatus: http.StatusBadRequest,
+				}
+			}
+
+			owners := make([]string, 0, len(r.Owners))
+			for _, id := range r.Owners {
+				if contains(owners, id) {
+					continue
+				}
+				if _, err := s.users.GetByID(ctx, id); err != nil {
+					if isNotFound(err) {
+						return &domain.DomainError{
+							Code:       domain.ErrorCodeBadRequest,
+							Message:    "unknown owner " + id,
+							HTTPStatus: http.StatusBadRequest,
+						}
+					}
+					return err
+				}
+				owners = append(owners, id)
+			}
+			normalized = append(normalized, OwnershipRule{Pattern: pattern, Owners: owners})
+		}
+
+		if err := s.teams.SetOwnershipRules(ctx, name, normalized); err != nil {
+			return err
+		}
+		result = normalized
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.OwnershipUpdated{
+				TeamName: name,
+				Rules:    len(normalized),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+func (s *service) ImportCodeowners(ctx context.Context, name, content string) (CodeownersImport, error) {
+	var result CodeownersImport
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ensureExists(ctx, name); err != nil {
+			return err
+		}
+
+		entries, err := ParseCodeowners(content)
+		if err != nil {
+			return &domain.DomainError{
+				Code:       domain.ErrorCodeBadRequest,
+				Message:    err.Error(),
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+
+		resolved := map[string]resolvedHandle{}
+		result.Rules = make([]OwnershipRule, 0, len(entries))
+		for _, e := range entries {
+			owners := []string{}
+			for _, h := range e.Handles {
+				r, err := s.resolveHandle(ctx, name, h, resolved)
+				if err != nil {
+					return err
+				}
+				if r.reason != "" {
+					result.Unknown = append(result.Unknown, UnknownOwner{Line: e.Line, Handle: h, Reason: r.reason})
+					continue
+				}
+				for _, id := range r.ids {
+					if !contains(owners, id) {
+						owners = append(owners, id)
+					}
+				}
+			}
+			result.Rules = append(result.Rules, OwnershipRule{Pattern: e.Pattern, Owners: owners})
+		}
+
+		if err := s.teams.SetOwnershipRules(ctx, name, result.Rules); err != nil {
+			return err
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.CodeownersImported{
+				TeamName:      name,
+				Rules:         len(result.Rules),
+				UnknownOwners: len(result.Unknown),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+type resolvedHandle struct {
+	ids    []string
+	reason UnknownReason
+}
+
+func (s *service) resolveHandle(ctx context.Context, teamName, handle string, cache map[string]resolvedHandle) (resolvedHandle, error) {
+	if r, ok := cache[handle]; ok {
+		return r, nil
+	}
+
+	var r resolvedHandle
+	name := strings.TrimPrefix(handle, "@")
+	switch {
+	case !strings.HasPrefix(handle, "@"):
+		// e-mail owners have no matching users row
+		r.reason = UnknownEmail
+	case strings.Contains(name, "/"):
+		t, err := s.teams.GetWithMembers(ctx, name[strings.LastIndex(name, "/")+1:])
+		if err != nil && !isNotFound(err) {
+			return r, err
+		}
+		for _, m := range t.Members {
+			r.ids = append(r.ids, m.ID)
+		}
+		if len(r.ids) == 0 {
+			r.reason = UnknownNotFound
+		}
+	default:
+		found, err := s.users.FindByHandle(ctx, name)
+		if err != nil {
+			return r, err
+		}
+		if len(found) > 1 {
+			// the same username in several teams: only a member of the
+			// importing team can be picked without guessing
+			var own []user.User
+			for _, u := range found {
+				if u.TeamName == teamName {
+					own = append(own, u)
+				}
+			}
+			if len(own) != 1 {
+				r.reason = UnknownAmbiguous
+				break
+			}
+			found = own
+		}
+		if len(found) == 0 {
+			r.reason = UnknownNotFound
+			break
+		}
+		r.ids = []string{found[0].ID}
+	}
+
+	cache[handle] = r
+	return r, nil
+}
+
+func (s *service) LookupOwners(ctx context.Context, name string, paths []string) ([]PathOwners, error) {
+	rules, err := s.GetOwnershipRules(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ownership := NewOwnership(rules)
+	res := make([]PathOwners, 0, len(paths))
+	for _, p := range paths {
+		po := PathOwners{Path: p, Owners: []string{}}
+		if rule, ok := ownership.Match(p); ok {
+			po.Pattern = rule.Pattern
+			po.Owners = append(po.Owners, rule.Owners...)
+		}
+		res = append(res, po)
+	}
+	return res, nil
+}
+
+func (s *service) AddPairRule(ctx context.Context, rule PairRule) (PairRule, error) {
+	if !rule.Kind.Valid() {
+		return PairRule{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "kind must be one of: exclude, prefer",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if rule.ReviewerID == rule.AuthorID {
+		return PairRule{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "reviewer and author must differ",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	var res PairRule
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ensureExists(ctx, rule.TeamName); err != nil {
+			return err
+		}
+		author, err := s.users.GetByID(ctx, rule.AuthorID)
+		if err != nil {
+			return err
+		}
+		if author.TeamName != rule.TeamName {
+			return &domain.DomainError{
+				Code:       domain.ErrorCodeBadRequest,
+				Message:    "user " + rule.AuthorID + " is not a member of team " + rule.TeamName,
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		if _, err := s.users.GetByID(ctx, rule.ReviewerID); err != nil {
+			return err
+		}
+
+		existing, err := s.teams.ListPairRules(ctx, rule.TeamName)
+		if err != nil {
+			return err
+		}
+		for _, r := range existing {
+			if r.ReviewerID != rule.ReviewerID || r.AuthorID != rule.AuthorID {
+				continue
+			}
+			if r.Kind != rule.Kind {
+				return &domain.DomainError{
+					Code:       domain.ErrorCodeBadRequest,
+					Message:    fmt.Sprintf("conflicting %s rule already exists for this pair", r.Kind),
+					HTTPStatus: http.StatusBadRequest,
+				}
+			}
+			res = r
+			return nil
+		}
+
+		res, err = s.teams.AddPairRule(ctx, rule)
+		if err != nil {
+			return err
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.PairRuleAdded{
+				TeamName:   res.TeamName,
+				RuleID:     res.ID,
+				ReviewerID: res.ReviewerID,
+				AuthorID:   res.AuthorID,
+				Kind:       string(res.Kind),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+func (s *service) ListPairRules(ctx context.Context, name string) ([]PairRule, error) {
+	if err := s.ensureExists(ctx, name); err != nil {
+		return nil, err
+	}
+	return s.teams.ListPairRules(ctx, name)
+}
+
+func (s *service) RemovePairRule(ctx context.Context, name string, id int64) error {
+	return s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.teams.DeletePairRule(ctx, name, id); err != nil {
+			return err
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.PairRuleRemoved{
+				TeamName: name,
+				RuleID:   id,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *service) ensureExists(ctx context.Context, name string) error {
+	exists, err := s.teams.Exists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "team not found",
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+	return nil
+}
+
+func (s *service) validatePartners(ctx context.Context, name string, partners []string) ([]string, error) {
+	res := make([]string, 0, len(partners))
+	for _, p := range partners {
+		p = strings.TrimSpace(p)
+		if p == "" || contains(res, p) {
+			continue
+		}
+		if p == name {
+			return nil, &domain.DomainError{
+				Code:       domain.ErrorCodeBadRequest,
+				Message:    "team cannot be its own partner",
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		exists, err := s.teams.Exists(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, &domain.DomainError{
+				Code:       domain.ErrorCodeBadRequest,
+				Message:    "unknown partner team " + p,
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+func isNotFound(err error) bool {
+	var de *domain.DomainError
+	return errors.As(err, &de) && de.Code == domain.ErrorCodeNotFound
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSettings(s Settings) error {
+	if s.MinReviewers < 0 || s.MaxReviewers < 1 || s.MinReviewers > s.MaxReviewers {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "reviewer count must satisfy 0 <= min_reviewers <= max_reviewers, max_reviewers >= 1",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if s.RequiredApprovals < 0 || s.RequiredApprovals > s.MaxReviewers {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "required_approvals must satisfy 0 <= required_approvals <= max_reviewers",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if s.RotationWindow < 0 {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "rotation_window must not be negative",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	return nil
+}
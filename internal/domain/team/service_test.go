@@ -3,6 +3,7 @@ package team_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"prservice/internal/domain"
@@ -18,7 +19,10 @@ func (uowStub) WithinTx(ctx context.Context, fn func(ctx context.Context) error)
 
 type eventBusFake struct{ events []domain.Event }
 
-func (e *eventBusFake) Publish(ctx context.Context, ev domain.Event) { e.events = append(e.events, ev) }
+func (e *eventBusFake) Publish(ctx context.Context, ev domain.Event) error {
+	e.events = append(e.events, ev)
+	return nil
+}
 
 type userRepoFake struct {
 	byID map[string]user.User
@@ -49,6 +53,27 @@ func (r *userRepoFake) GetByID(ctx context.Context, userID string) (user.User, e
 	}
 	return u, nil
 }
+func (r *userRepoFake) FindByHandle(ctx context.Context, handle string) ([]user.User, error) {
+	if u, ok := r.byID[handle]; ok {
+		return []user.User{u}, nil
+	}
+	var res []user.User
+	for _, u := range r.byID {
+		if strings.EqualFold(u.Username, handle) {
+			res = append(res, u)
+		}
+	}
+	return res, nil
+}
+func (r *userRepoFake) AddUnavailability(ctx context.Context, u user.Unavailability) (user.Unavailability, error) {
+	return u, nil
+}
+func (r *userRepoFake) ListUnavailability(ctx context.Context, userID string) ([]user.Unavailability, error) {
+	return nil, nil
+}
+func (r *userRepoFake) DeleteUnavailability(ctx context.Context, userID string, id int64) error {
+	return nil
+}
 func (r *userRepoFake) GetActiveTeamMembersExcept(ctx context.Context, teamName, excludeUserID string) ([]user.User, error) {
 	var res []user.User
 	for _, u := range r.byID {
@@ -60,24 +85,46 @@ func (r *userRepoFake) GetActiveTeamMembersExcept(ctx context.Context, teamName,
 }
 
 type teamRepoFake struct {
-	created map[string]bool
-	users   *userRepoFake
+	created  map[string]bool
+	settings map[string]team.Settings
+	rules    map[string][]team.OwnershipRule
+	pairs    []team.PairRule
+	users    *userRepoFake
 }
 
 func newTeamRepoFake(u *userRepoFake) *teamRepoFake {
-	return &teamRepoFake{created: map[string]bool{}, users: u}
+	return &teamRepoFake{
+		created:  map[string]bool{},
+		settings: map[string]team.Settings{},
+		rules:    map[string][]team.OwnershipRule{},
+		users:    u,
+	}
 }
 
 func (r *teamRepoFake) Exists(ctx context.Context, name string) (bool, error) {
 	return r.created[name], nil
 }
-func (r *teamRepoFake) Create(ctx context.Context, name string) error {
+func (r *teamRepoFake) Create(ctx context.Context, name string, settings team.Settings) error {
 	if r.created[name] {
 		return errors.New("exists")
 	}
 	r.created[name] = true
+	r.settings[name] = settings
+	return nil
+}
+func (r *teamRepoFake) UpdateSettings(ctx context.Context, name string, settings team.Settings) error {
+	if !r.created[name] {
+		return &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "team not found", HTTPStatus: 404}
+	}
+	r.settings[name] = settings
 	return nil
 }
+func (r *teamRepoFake) GetSettings(ctx context.Context, name string) (team.Settings, error) {
+	if !r.created[name] {
+		return team.Settings{}, &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "team not found", HTTPStatus: 404}
+	}
+	return r.settings[name], nil
+}
 func (r *teamRepoFake) GetWithMembers(ctx context.Context, name string) (team.Team, error) {
 	if !r.created[name] {
 		return team.Team{}, &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "team not found", HTTPStatus: 404}
@@ -88,7 +135,37 @@ func (r *teamRepoFake) GetWithMembers(ctx context.Context, name string) (team.Te
 			members = append(members, team.Member{ID: u.ID, Username: u.Username, IsActive: u.IsActive})
 		}
 	}
-	return team.Team{Name: name, Members: members}, nil
+	return team.Team{Name: name, Members: members, Settings: r.settings[name]}, nil
+}
+func (r *teamRepoFake) GetOwnershipRules(ctx context.Context, name string) ([]team.OwnershipRule, error) {
+	return r.rules[name], nil
+}
+func (r *teamRepoFake) SetOwnershipRules(ctx context.Context, name string, rules []team.OwnershipRule) error {
+	r.rules[name] = rules
+	return nil
+}
+func (r *teamRepoFake) AddPairRule(ctx context.Context, rule team.PairRule) (team.PairRule, error) {
+	rule.ID = int64(len(r.pairs) + 1)
+	r.pairs = append(r.pairs, rule)
+	return rule, nil
+}
+func (r *teamRepoFake) ListPairRules(ctx context.Context, name string) ([]team.PairRule, error) {
+	var res []team.PairRule
+	for _, rule := range r.pairs {
+		if rule.TeamName == name && rule.ID > 0 {
+			res = append(res, rule)
+		}
+	}
+	return res, nil
+}
+func (r *teamRepoFake) DeletePairRule(ctx context.Context, name string, id int64) error {
+	for i, rule := range r.pairs {
+		if rule.TeamName == name && rule.ID == id {
+			r.pairs[i].ID = 0
+			return nil
+		}
+	}
+	return &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "pair rule not found", HTTPStatus: 404}
 }
 
 func TestAddTeam_Success(t *testing.T) {
@@ -97,7 +174,7 @@ func TestAddTeam_Success(t *testing.T) {
 	teams := newTeamRepoFake(users)
 	events := &eventBusFake{}
 
-	svc := team.NewService(uow, teams, users, events)
+	svc := team.NewService(uow, teams, users, nil, events)
 
 	tm := team.Team{
 		Name: "backend",
@@ -117,7 +194,7 @@ func TestAddTeam_Success(t *testing.T) {
 	if !teams.created["backend"] {
 		t.Fatalf("team was not created in repo")
 	}
-	if len(events.events) != 1 || events.events[0].Type != "team.created" {
+	if len(events.events) != 1 || events.events[0].EventType() != "team.created" {
 		t.Fatalf("expected team.created event, got %+v", events.events)
 	}
 }
@@ -130,7 +207,7 @@ func TestAddTeam_AlreadyExists(t *testing.T) {
 
 	teams.created["backend"] = true
 
-	svc := team.NewService(uow, teams, users, events)
+	svc := team.NewService(uow, teams, users, nil, events)
 	_, err := svc.AddTeam(context.Background(), team.Team{Name: "backend"})
 	var de *domain.DomainError
 	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeTeamExists {
@@ -138,6 +215,23 @@ func TestAddTeam_AlreadyExists(t *testing.T) {
 	}
 }
 
+func TestAddTeam_RequiredApprovalsAboveMaxReviewers(t *testing.T) {
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	svc := team.NewService(uowStub{}, teams, users, nil, &eventBusFake{})
+
+	settings := team.DefaultSettings()
+	settings.RequiredApprovals = settings.MaxReviewers + 1
+	_, err := svc.AddTeam(context.Background(), team.Team{Name: "backend", Settings: settings})
+	var de *domain.DomainError
+	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST, got %v", err)
+	}
+	if teams.created["backend"] {
+		t.Fatalf("team with unreachable required_approvals must not be created")
+	}
+}
+
 func TestGetTeam(t *testing.T) {
 	uow := uowStub{}
 	users := newUserRepoFake()
@@ -150,7 +244,7 @@ func TestGetTeam(t *testing.T) {
 		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: false},
 	})
 
-	svc := team.NewService(uow, teams, users, events)
+	svc := team.NewService(uow, teams, users, nil, events)
 	got, err := svc.GetTeam(context.Background(), "backend")
 	if err != nil {
 		t.Fatalf("GetTeam: %v", err)
@@ -159,3 +253,367 @@ func TestGetTeam(t *testing.T) {
 		t.Fatalf("unexpected team: %+v", got)
 	}
 }
+
+func TestUpdateSettings(t *testing.T) {
+	uow := uowStub{}
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	events := &eventBusFake{}
+
+	teams.created["backend"] = true
+	teams.settings["backend"] = team.DefaultSettings()
+
+	svc := team.NewService(uow, teams, users, nil, events)
+
+	minReviewers, maxReviewers := 1, 3
+	got, err := svc.UpdateSettings(context.Background(), "backend", team.SettingsUpdate{
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if got.Settings.MinReviewers != 1 || got.Settings.MaxReviewers != 3 {
+		t.Fatalf("unexpected settings: %+v", got.Settings)
+	}
+	if len(events.events) != 1 || events.events[0].EventType() != "team.updated" {
+		t.Fatalf("expected team.updated event, got %+v", events.events)
+	}
+
+	tooMany := 4
+	_, err = svc.UpdateSettings(context.Background(), "backend", team.SettingsUpdate{MinReviewers: &tooMany})
+	var de *domain.DomainError
+	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST, got %v", err)
+	}
+
+	approvals := 4
+	_, err = svc.UpdateSettings(context.Background(), "backend", team.SettingsUpdate{RequiredApprovals: &approvals})
+	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST for required_approvals > max_reviewers, got %v", err)
+	}
+	lower := 2
+	_, err = svc.UpdateSettings(context.Background(), "backend", team.SettingsUpdate{MaxReviewers: &lower, MinReviewers: &minReviewers, RequiredApprovals: &maxReviewers})
+	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST when max_reviewers drops below required_approvals, got %v", err)
+	}
+
+	negative := -1
+	_, err = svc.UpdateSettings(context.Background(), "backend", team.SettingsUpdate{RotationWindow: &negative})
+	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST for negative rotation_window, got %v", err)
+	}
+
+	_, err = svc.UpdateSettings(context.Background(), "missing", team.SettingsUpdate{})
+	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeNotFound {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+}
+
+type redistributorFake struct {
+	team string
+	ids  []string
+}
+
+func (r *redistributorFake) RedistributeReviews(ctx context.Context, teamName string, userIDs []string) (user.ReassignReport, error) {
+	r.team, r.ids = teamName, userIDs
+	report := user.ReassignReport{}
+	for _, id := range userIDs {
+		report.Reassigned = append(report.Reassigned, user.Reassignment{PRID: "pr-" + id, UserID: id, ReplacedBy: "u9"})
+	}
+	return report, nil
+}
+
+func TestDeactivateUsers(t *testing.T) {
+	ctx := context.Background()
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	events := &eventBusFake{}
+	reviews := &redistributorFake{}
+
+	teams.created["backend"] = true
+	_ = users.UpsertInTeam(ctx, "backend", []user.User{
+		{ID: "u1", Username: "Alice", IsActive: true},
+		{ID: "u2", Username: "Bob", IsActive: true},
+		{ID: "u3", Username: "Eve", IsActive: true},
+	})
+	_ = users.UpsertInTeam(ctx, "frontend", []user.User{
+		{ID: "u4", Username: "John", IsActive: true},
+	})
+
+	svc := team.NewService(uowStub{}, teams, users, reviews, events)
+
+	res, err := svc.DeactivateUsers(ctx, "backend", []string{"u1", "u2", "u1"})
+	if err != nil {
+		t.Fatalf("DeactivateUsers: %v", err)
+	}
+	if len(res.Users) != 2 || res.Users[0].IsActive || res.Users[1].IsActive {
+		t.Fatalf("expected 2 deactivated users, got %+v", res.Users)
+	}
+	if reviews.team != "backend" || len(reviews.ids) != 2 {
+		t.Fatalf("unexpected redistribution call: %s %v", reviews.team, reviews.ids)
+	}
+	if len(res.Reassignment.Reassigned) != 2 {
+		t.Fatalf("unexpected report: %+v", res.Reassignment)
+	}
+	if len(events.events) != 1 || events.events[0].EventType() != "team.users_deactivated" {
+		t.Fatalf("expected team.users_deactivated event, got %+v", events.events)
+	}
+
+	_, err = svc.DeactivateUsers(ctx, "backend", []string{"u3", "u4"})
+	var de *domain.DomainError
+	if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST for foreign user, got %v", err)
+	}
+
+	_, err = svc.DeactivateUsers(ctx, "missing", []string{"u3"})
+	if !errors.As(err, &de) || de.Code != domain.ErrorCodeNotFound {
+		t.Fatalf("expected NOT_FOUND for missing team, got %v", err)
+	}
+}
+
+func TestOwnership_Match(t *testing.T) {
+	o := team.NewOwnership([]team.OwnershipRule{
+		{Pattern: "*", Owners: []string{"all"}},
+		{Pattern: "*.go", Owners: []string{"gopher"}},
+		{Pattern: "/docs/", Owners: []string{"writer"}},
+		{Pattern: "api/*", Owners: []string{"api"}},
+		{Pattern: "**/migrations", Owners: []string{"dba"}},
+		{Pattern: "internal/app/**", Owners: []string{"app"}},
+	})
+
+	cases := map[string]string{
+		"README.md":                      "all",
+		"cmd/app/main.go":                "gopher",
+		"docs/guide/intro.md":            "writer",
+		"docs":                           "all",
+		"api/openapi.yml":                "api",
+		"api/v1/openapi.yml":             "all",
+		"db/migrations/01_init.sql":      "dba",
+		"migrations/01_init.sql":         "dba",
+		"internal/app/http/router.go":    "app",
+		"/internal/app/dto/team_dto.go":  "app",
+		"internal/domain/team/entity.go": "gopher",
+	}
+	for path, want := range cases {
+		rule, ok := o.Match(path)
+		if !ok || rule.Owners[0] != want {
+			t.Errorf("%s: expected owner %s, got %+v (matched=%v)", path, want, rule, ok)
+		}
+	}
+
+	owners := o.Owners([]string{"cmd/app/main.go", "docs/a.md", "pkg/x.go"})
+	if len(owners) != 2 || owners[0] != "gopher" || owners[1] != "writer" {
+		t.Fatalf("unexpected owners: %v", owners)
+	}
+}
+
+func TestSetOwnershipRules(t *testing.T) {
+	ctx := context.Background()
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	events := &eventBusFake{}
+	svc := team.NewService(uowStub{}, teams, users, nil, events)
+
+	if _, err := svc.SetOwnershipRules(ctx, "backend", nil); err == nil {
+		t.Fatalf("expected NOT_FOUND for missing team")
+	}
+
+	if _, err := svc.AddTeam(ctx, team.Team{Name: "backend", Members: []team.Member{
+		{ID: "u1", Username: "Alice", IsActive: true},
+	}}); err != nil {
+		t.Fatalf("AddTeam: %v", err)
+	}
+
+	_, err := svc.SetOwnershipRules(ctx, "backend", []team.OwnershipRule{{Pattern: "*.go", Owners: []string{"ghost"}}})
+	var de *domain.DomainError
+	if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST for unknown owner, got %v", err)
+	}
+	if _, err := svc.SetOwnershipRules(ctx, "backend", []team.OwnershipRule{{Pattern: " ", Owners: []string{"u1"}}}); err == nil {
+		t.Fatalf("expected BAD_REQUEST for empty pattern")
+	}
+
+	rules, err := svc.SetOwnershipRules(ctx, "backend", []team.OwnershipRule{{Pattern: " *.go ", Owners: []string{"u1", "u1"}}})
+	if err != nil {
+		t.Fatalf("SetOwnershipRules: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Pattern != "*.go" || len(rules[0].Owners) != 1 {
+		t.Fatalf("rules must be normalized, got %+v", rules)
+	}
+	stored, _ := svc.GetOwnershipRules(ctx, "backend")
+	if len(stored) != 1 {
+		t.Fatalf("expected stored rules, got %+v", stored)
+	}
+}
+
+func TestParseCodeowners(t *testing.T) {
+	entries, err := team.ParseCodeowners(`# global owners
+*       @alice
+
+/docs/  @bob docs@example.com # inline comment
+\#notes @carol
+`)
+	if err != nil {
+		t.Fatalf("ParseCodeowners: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	if entries[1].Line != 4 || entries[1].Pattern != "/docs/" || len(entries[1].Handles) != 2 {
+		t.Fatalf("unexpected entry: %+v", entries[1])
+	}
+	if entries[2].Pattern != "#notes" {
+		t.Fatalf("escaped hash must be kept, got %q", entries[2].Pattern)
+	}
+
+	if _, err := team.ParseCodeowners("!*.go @alice"); err == nil {
+		t.Fatalf("expected error for negated pattern")
+	}
+}
+
+func TestImportCodeowners(t *testing.T) {
+	ctx := context.Background()
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	svc := team.NewService(uowStub{}, teams, users, nil, &eventBusFake{})
+
+	for _, tm := range []team.Team{
+		{Name: "backend", Members: []team.Member{{ID: "u1", Username: "alice", IsActive: true}}},
+		{Name: "platform", Members: []team.Member{{ID: "u2", Username: "bob", IsActive: true}, {ID: "u3", Username: "carol", IsActive: true}}},
+	} {
+		if _, err := svc.AddTeam(ctx, tm); err != nil {
+			t.Fatalf("AddTeam: %v", err)
+		}
+	}
+
+	res, err := svc.ImportCodeowners(ctx, "backend", `*.go @Alice @ghost
+/deploy/ @org/platform dev@example.com
+`)
+	if err != nil {
+		t.Fatalf("ImportCodeowners: %v", err)
+	}
+	if len(res.Rules) != 2 || len(res.Rules[0].Owners) != 1 || res.Rules[0].Owners[0] != "u1" {
+		t.Fatalf("unexpected rules: %+v", res.Rules)
+	}
+	if len(res.Rules[1].Owners) != 2 {
+		t.Fatalf("team handle must expand to members, got %+v", res.Rules[1])
+	}
+	if len(res.Unknown) != 2 || res.Unknown[0].Handle != "@ghost" || res.Unknown[0].Reason != team.UnknownNotFound ||
+		res.Unknown[1].Line != 2 || res.Unknown[1].Reason != team.UnknownEmail {
+		t.Fatalf("unexpected unknown handles: %+v", res.Unknown)
+	}
+
+	owners, err := svc.LookupOwners(ctx, "backend", []string{"cmd/main.go", "deploy/app.yml", "README.md"})
+	if err != nil {
+		t.Fatalf("LookupOwners: %v", err)
+	}
+	if owners[0].Pattern != "*.go" || len(owners[1].Owners) != 2 || owners[2].Pattern != "" {
+		t.Fatalf("unexpected lookup result: %+v", owners)
+	}
+}
+
+func TestImportCodeowners_AmbiguousHandles(t *testing.T) {
+	ctx := context.Background()
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	svc := team.NewService(uowStub{}, teams, users, nil, &eventBusFake{})
+
+	for _, tm := range []team.Team{
+		{Name: "backend", Members: []team.Member{{ID: "u1", Username: "alice", IsActive: true}}},
+		{Name: "platform", Members: []team.Member{{ID: "u2", Username: "Alice", IsActive: true}, {ID: "u3", Username: "bob", IsActive: true}}},
+		{Name: "mobile", Members: []team.Member{{ID: "u4", Username: "bob", IsActive: true}}},
+	} {
+		if _, err := svc.AddTeam(ctx, tm); err != nil {
+			t.Fatalf("AddTeam: %v", err)
+		}
+	}
+
+	res, err := svc.ImportCodeowners(ctx, "backend", "*.go @alice @bob @u3\n")
+	if err != nil {
+		t.Fatalf("ImportCodeowners: %v", err)
+	}
+	if owners := res.Rules[0].Owners; len(owners) != 2 || owners[0] != "u1" || owners[1] != "u3" {
+		t.Fatalf("username shared across teams must resolve to the importing team's member, got %v", owners)
+	}
+	if len(res.Unknown) != 1 || res.Unknown[0].Handle != "@bob" || res.Unknown[0].Reason != team.UnknownAmbiguous {
+		t.Fatalf("expected @bob reported as ambiguous, got %+v", res.Unknown)
+	}
+}
+
+func TestUpdateSettings_PartnerTeams(t *testing.T) {
+	ctx := context.Background()
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	svc := team.NewService(uowStub{}, teams, users, nil, &eventBusFake{})
+
+	for _, name := range []string{"backend", "platform"} {
+		teams.created[name] = true
+		teams.settings[name] = team.DefaultSettings()
+	}
+
+	var de *domain.DomainError
+	for _, partners := range [][]string{{"backend"}, {"missing"}} {
+		_, err := svc.UpdateSettings(ctx, "backend", team.SettingsUpdate{PartnerTeams: &partners})
+		if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+			t.Fatalf("partners %v: expected BAD_REQUEST, got %v", partners, err)
+		}
+	}
+
+	partners := []string{"platform", " platform "}
+	got, err := svc.UpdateSettings(ctx, "backend", team.SettingsUpdate{PartnerTeams: &partners})
+	if err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if len(got.Settings.PartnerTeams) != 1 || got.Settings.PartnerTeams[0] != "platform" {
+		t.Fatalf("unexpected partner teams: %v", got.Settings.PartnerTeams)
+	}
+}
+
+func TestPairRules(t *testing.T) {
+	ctx := context.Background()
+	users := newUserRepoFake()
+	teams := newTeamRepoFake(users)
+	svc := team.NewService(uowStub{}, teams, users, nil, &eventBusFake{})
+
+	teams.created["backend"] = true
+	teams.settings["backend"] = team.DefaultSettings()
+	users.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}
+	users.byID["u2"] = user.User{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true}
+	users.byID["p1"] = user.User{ID: "p1", Username: "Kim", TeamName: "platform", IsActive: true}
+
+	var de *domain.DomainError
+	for _, rule := range []team.PairRule{
+		{TeamName: "backend", ReviewerID: "u2", AuthorID: "u1", Kind: "block"},
+		{TeamName: "backend", ReviewerID: "u1", AuthorID: "u1", Kind: team.PairRuleExclude},
+		{TeamName: "backend", ReviewerID: "u1", AuthorID: "p1", Kind: team.PairRuleExclude},
+	} {
+		if _, err := svc.AddPairRule(ctx, rule); !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+			t.Fatalf("rule %+v: expected BAD_REQUEST, got %v", rule, err)
+		}
+	}
+
+	added, err := svc.AddPairRule(ctx, team.PairRule{TeamName: "backend", ReviewerID: "p1", AuthorID: "u1", Kind: team.PairRuleExclude})
+	if err != nil {
+		t.Fatalf("AddPairRule: %v", err)
+	}
+	again, err := svc.AddPairRule(ctx, team.PairRule{TeamName: "backend", ReviewerID: "p1", AuthorID: "u1", Kind: team.PairRuleExclude})
+	if err != nil || again.ID != added.ID {
+		t.Fatalf("repeated rule must be idempotent, got %+v, %v", again, err)
+	}
+	_, err = svc.AddPairRule(ctx, team.PairRule{TeamName: "backend", ReviewerID: "p1", AuthorID: "u1", Kind: team.PairRulePrefer})
+	if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("conflicting rule: expected BAD_REQUEST, got %v", err)
+	}
+
+	if err := svc.RemovePairRule(ctx, "backend", added.ID); err != nil {
+		t.Fatalf("RemovePairRule: %v", err)
+	}
+	if err := svc.RemovePairRule(ctx, "backend", added.ID); !errors.As(err, &de) || de.Code != domain.ErrorCodeNotFound {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+	rules, err := svc.ListPairRules(ctx, "backend")
+	if err != nil || len(rules) != 0 {
+		t.Fatalf("expected no rules, got %+v, %v", rules, err)
+	}
+}
@@ -1,8 +1,54 @@
 package user
 
+import "time"
+
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "junior"
+	SeniorityMiddle Seniority = "middle"
+	SenioritySenior Seniority = "senior"
+)
+
+func (s Seniority) Valid() bool {
+	switch s {
+	case SeniorityJunior, SeniorityMiddle, SenioritySenior:
+		return true
+	}
+	return false
+}
+
 type User struct {
-	ID       string
-	Username string
-	TeamName string
-	IsActive bool
+	ID             string
+	Username       string
+	TeamName       string
+	IsActive       bool
+	ReviewWeight   int
+	MaxOpenReviews int
+	Expertise      []string
+	Seniority      Seniority
+}
+
+type Reassignment struct {
+	PRID       string
+	UserID     string
+	ReplacedBy string
+}
+
+type ReassignReport struct {
+	Reassigned       []Reassignment
+	WithoutCandidate []Reassignment
+}
+
+type Unavailability struct {
+	ID       int64
+	UserID   string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+// Covers reports whether the window is in effect at t.
+func (u Unavailability) Covers(t time.Time) bool {
+	return !t.Before(u.StartsAt) && t.Before(u.EndsAt)
 }
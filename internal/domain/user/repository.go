@@ -6,5 +6,9 @@ type Repository interface {
 	UpsertInTeam(ctx context.Context, teamName string, members []User) error
 	SetActive(ctx context.Context, userID string, isActive bool) (User, error)
 	GetByID(ctx context.Context, userID string) (User, error)
+	FindByHandle(ctx context.Context, handle string) ([]User, error)
 	GetActiveTeamMembersExcept(ctx context.Context, teamName, excludeUserID string) ([]User, error)
+	AddUnavailability(ctx context.Context, u Unavailability) (Unavailability, error)
+	ListUnavailability(ctx context.Context, userID string) ([]Unavailability, error)
+	DeleteUnavailability(ctx context.Context, userID string, id int64) error
 }
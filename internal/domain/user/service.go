@@ -2,30 +2,49 @@ package user
 
 import (
 	"context"
+	"net/http"
 
 	"prservice/internal/domain"
 )
 
 type Service interface {
-	SetUserActive(ctx context.Context, userID string, isActive bool) (User, error)
+	SetUserActive(ctx context.Context, userID string, isActive bool, reassign *bool) (User, *ReassignReport, error)
+	AddUnavailability(ctx context.Context, u Unavailability) (Unavailability, error)
+	ListUnavailability(ctx context.Context, userID string) ([]Unavailability, error)
+	RemoveUnavailability(ctx context.Context, userID string, id int64) error
+}
+
+type ReviewReassigner interface {
+	ReassignOpenReviews(ctx context.Context, userID string) (ReassignReport, error)
+}
+
+// DeactivationPolicy decides whether reviews are reassigned when a member
+// is deactivated without an explicit reassign flag.
+type DeactivationPolicy interface {
+	ReassignOnDeactivate(ctx context.Context, teamName string) (bool, error)
 }
 
 type service struct {
-	uow    domain.UnitOfWork
-	users  Repository
-	events domain.EventBus
+	uow     domain.UnitOfWork
+	users   Repository
+	reviews ReviewReassigner
+	policy  DeactivationPolicy
+	events  domain.EventPublisher
 }
 
-func NewService(uow domain.UnitOfWork, users Repository, events domain.EventBus) Service {
+func NewService(uow domain.UnitOfWork, users Repository, reviews ReviewReassigner, policy DeactivationPolicy, events domain.EventPublisher) Service {
 	return &service{
-		uow:    uow,
-		users:  users,
-		events: events,
+		uow:     uow,
+		users:   users,
+		reviews: reviews,
+		policy:  policy,
+		events:  events,
 	}
 }
 
-func (s *service) SetUserActive(ctx context.Context, userID string, isActive bool) (User, error) {
+func (s *service) SetUserActive(ctx context.Context, userID string, isActive bool, reassign *bool) (User, *ReassignReport, error) {
 	var res User
+	var report *ReassignReport
 
 	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
 		u, err := s.users.SetActive(ctx, userID, isActive)
@@ -34,17 +53,100 @@ func (s *service) SetUserActive(ctx context.Context, userID string, isActive boo
 		}
 		res = u
 
+		if !isActive && s.reviews != nil {
+			var reassignReviews bool
+			switch {
+			case reassign != nil:
+				reassignReviews = *reassign
+			case s.policy != nil:
+				if reassignReviews, err = s.policy.ReassignOnDeactivate(ctx, u.TeamName); err != nil {
+					return err
+				}
+			}
+			if reassignReviews {
+				r, err := s.reviews.ReassignOpenReviews(ctx, u.ID)
+				if err != nil {
+					return err
+				}
+				report = &r
+			}
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.UserActivityChanged{
+				UserID:   u.ID,
+				TeamName: u.TeamName,
+				IsActive: u.IsActive,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return res, report, err
+}
+
+func (s *service) AddUnavailability(ctx context.Context, u Unavailability) (Unavailability, error) {
+	if !u.EndsAt.After(u.StartsAt) {
+		return Unavailability{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "ends_at must be after starts_at",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	var res Unavailability
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.users.GetByID(ctx, u.UserID); err != nil {
+			return err
+		}
+
+		created, err := s.users.AddUnavailability(ctx, u)
+		if err != nil {
+			return err
+		}
+		res = created
+
 		if s.events != nil {
-			s.events.Publish(ctx, domain.Event{
-				Type: "user.set_active",
-				Payload: map[string]any{
-					"user_id":   u.ID,
-					"is_active": u.IsActive,
-				},
-			})
+			if err := s.events.Publish(ctx, domain.UnavailabilityAdded{
+				UserID:           created.UserID,
+				UnavailabilityID: created.ID,
+				StartsAt:         created.StartsAt,
+				EndsAt:           created.EndsAt,
+			}); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
 
 	return res, err
 }
+
+func (s *service) ListUnavailability(ctx context.Context, userID string) ([]Unavailability, error) {
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.users.ListUnavailability(ctx, userID)
+}
+
+func (s *service) RemoveUnavailability(ctx context.Context, userID string, id int64) error {
+	return s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.users.DeleteUnavailability(ctx, userID, id); err != nil {
+			return err
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, domain.UnavailabilityRemoved{
+				UserID:           userID,
+				UnavailabilityID: id,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -3,7 +3,9 @@ package user_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"prservice/internal/domain"
 	"prservice/internal/domain/user"
@@ -17,9 +19,15 @@ func (uowStub) WithinTx(ctx context.Context, fn func(ctx context.Context) error)
 
 type eventBusFake struct{ events []domain.Event }
 
-func (e *eventBusFake) Publish(ctx context.Context, ev domain.Event) { e.events = append(e.events, ev) }
+func (e *eventBusFake) Publish(ctx context.Context, ev domain.Event) error {
+	e.events = append(e.events, ev)
+	return nil
+}
 
-type userRepoFake struct{ byID map[string]user.User }
+type userRepoFake struct {
+	byID map[string]user.User
+	away []user.Unavailability
+}
 
 func newUserRepoFake() *userRepoFake { return &userRepoFake{byID: map[string]user.User{}} }
 
@@ -29,6 +37,29 @@ func (r *userRepoFake) UpsertInTeam(ctx context.Context, teamName string, member
 func (r *userRepoFake) GetActiveTeamMembersExcept(ctx context.Context, teamName, excludeUserID string) ([]user.User, error) {
 	return nil, nil
 }
+func (r *userRepoFake) AddUnavailability(ctx context.Context, u user.Unavailability) (user.Unavailability, error) {
+	u.ID = int64(len(r.away) + 1)
+	r.away = append(r.away, u)
+	return u, nil
+}
+func (r *userRepoFake) ListUnavailability(ctx context.Context, userID string) ([]user.Unavailability, error) {
+	var res []user.Unavailability
+	for _, u := range r.away {
+		if u.UserID == userID {
+			res = append(res, u)
+		}
+	}
+	return res, nil
+}
+func (r *userRepoFake) DeleteUnavailability(ctx context.Context, userID string, id int64) error {
+	for i, u := range r.away {
+		if u.ID == id && u.UserID == userID {
+			r.away = append(r.away[:i], r.away[i+1:]...)
+			return nil
+		}
+	}
+	return &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "unavailability not found", HTTPStatus: 404}
+}
 func (r *userRepoFake) GetByID(ctx context.Context, userID string) (user.User, error) {
 	u, ok := r.byID[userID]
 	if !ok {
@@ -36,6 +67,18 @@ func (r *userRepoFake) GetByID(ctx context.Context, userID string) (user.User, e
 	}
 	return u, nil
 }
+func (r *userRepoFake) FindByHandle(ctx context.Context, handle string) ([]user.User, error) {
+	if u, ok := r.byID[handle]; ok {
+		return []user.User{u}, nil
+	}
+	var res []user.User
+	for _, u := range r.byID {
+		if strings.EqualFold(u.Username, handle) {
+			res = append(res, u)
+		}
+	}
+	return res, nil
+}
 func (r *userRepoFake) SetActive(ctx context.Context, userID string, isActive bool) (user.User, error) {
 	u, ok := r.byID[userID]
 	if !ok {
@@ -46,6 +89,23 @@ func (r *userRepoFake) SetActive(ctx context.Context, userID string, isActive bo
 	return u, nil
 }
 
+type reassignerFake struct {
+	calls []string
+}
+
+type policyFake map[string]bool
+
+func (p policyFake) ReassignOnDeactivate(ctx context.Context, teamName string) (bool, error) {
+	return p[teamName], nil
+}
+
+func (r *reassignerFake) ReassignOpenReviews(ctx context.Context, userID string) (user.ReassignReport, error) {
+	r.calls = append(r.calls, userID)
+	return user.ReassignReport{
+		Reassigned: []user.Reassignment{{PRID: "pr-1", ReplacedBy: "u3"}},
+	}, nil
+}
+
 func TestSetUserActive(t *testing.T) {
 	uow := uowStub{}
 	repo := newUserRepoFake()
@@ -53,16 +113,16 @@ func TestSetUserActive(t *testing.T) {
 
 	repo.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: false}
 
-	svc := user.NewService(uow, repo, events)
+	svc := user.NewService(uow, repo, nil, nil, events)
 
-	u, err := svc.SetUserActive(context.Background(), "u1", true)
+	u, _, err := svc.SetUserActive(context.Background(), "u1", true, nil)
 	if err != nil {
 		t.Fatalf("SetUserActive: %v", err)
 	}
 	if !u.IsActive {
 		t.Fatalf("user should be active")
 	}
-	if len(events.events) != 1 || events.events[0].Type != "user.set_active" {
+	if len(events.events) != 1 || events.events[0].EventType() != "user.set_active" {
 		t.Fatalf("expected user.set_active event, got %+v", events.events)
 	}
 }
@@ -72,10 +132,113 @@ func TestSetUserActive_NotFound(t *testing.T) {
 	repo := newUserRepoFake()
 	events := &eventBusFake{}
 
-	svc := user.NewService(uow, repo, events)
-	_, err := svc.SetUserActive(context.Background(), "missing", true)
+	svc := user.NewService(uow, repo, nil, nil, events)
+	_, _, err := svc.SetUserActive(context.Background(), "missing", true, nil)
 	var de *domain.DomainError
 	if err == nil || !errors.As(err, &de) || de.Code != domain.ErrorCodeNotFound {
 		t.Fatalf("expected NOT_FOUND, got %v", err)
 	}
 }
+
+func TestSetUserActive_Reassign(t *testing.T) {
+	ctx := context.Background()
+	repo := newUserRepoFake()
+	repo.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}
+	repo.byID["u2"] = user.User{ID: "u2", Username: "Bob", TeamName: "frontend", IsActive: true}
+	reviews := &reassignerFake{}
+	svc := user.NewService(uowStub{}, repo, reviews, policyFake{"frontend": true}, &eventBusFake{})
+	yes, no := true, false
+
+	_, report, err := svc.SetUserActive(ctx, "u1", false, nil)
+	if err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	if report != nil || len(reviews.calls) != 0 {
+		t.Fatalf("no reassignment expected without flag or policy, got %+v", report)
+	}
+
+	_, report, err = svc.SetUserActive(ctx, "u1", false, &yes)
+	if err != nil {
+		t.Fatalf("SetUserActive with flag: %v", err)
+	}
+	if report == nil || len(report.Reassigned) != 1 {
+		t.Fatalf("expected reassignment report, got %+v", report)
+	}
+
+	_, report, err = svc.SetUserActive(ctx, "u2", false, &no)
+	if err != nil {
+		t.Fatalf("SetUserActive with reassign=false: %v", err)
+	}
+	if report != nil {
+		t.Fatalf("explicit reassign=false must override team policy, got %+v", report)
+	}
+
+	_, report, err = svc.SetUserActive(ctx, "u2", false, nil)
+	if err != nil {
+		t.Fatalf("SetUserActive with policy: %v", err)
+	}
+	if report == nil {
+		t.Fatalf("team policy must trigger reassignment")
+	}
+
+	_, report, err = svc.SetUserActive(ctx, "u2", true, &yes)
+	if err != nil {
+		t.Fatalf("SetUserActive activate: %v", err)
+	}
+	if report != nil {
+		t.Fatalf("activation must not reassign, got %+v", report)
+	}
+	if want := []string{"u1", "u2"}; len(reviews.calls) != len(want) || reviews.calls[0] != want[0] || reviews.calls[1] != want[1] {
+		t.Fatalf("unexpected reassign calls %v", reviews.calls)
+	}
+}
+
+func TestUnavailability(t *testing.T) {
+	ctx := context.Background()
+	repo := newUserRepoFake()
+	repo.byID["u1"] = user.User{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}
+	events := &eventBusFake{}
+	svc := user.NewService(uowStub{}, repo, nil, nil, events)
+
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	var de *domain.DomainError
+
+	_, err := svc.AddUnavailability(ctx, user.Unavailability{UserID: "u1", StartsAt: start, EndsAt: start})
+	if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+		t.Fatalf("expected BAD_REQUEST for empty window, got %v", err)
+	}
+	_, err = svc.AddUnavailability(ctx, user.Unavailability{UserID: "missing", StartsAt: start, EndsAt: start.Add(time.Hour)})
+	if !errors.As(err, &de) || de.Code != domain.ErrorCodeNotFound {
+		t.Fatalf("expected NOT_FOUND for unknown user, got %v", err)
+	}
+
+	created, err := svc.AddUnavailability(ctx, user.Unavailability{
+		UserID:   "u1",
+		StartsAt: start,
+		EndsAt:   start.AddDate(0, 0, 14),
+		Reason:   "vacation",
+	})
+	if err != nil {
+		t.Fatalf("AddUnavailability: %v", err)
+	}
+	if created.ID == 0 {
+		t.Fatalf("expected id to be assigned")
+	}
+
+	list, err := svc.ListUnavailability(ctx, "u1")
+	if err != nil || len(list) != 1 || list[0].Reason != "vacation" {
+		t.Fatalf("unexpected list %+v, err %v", list, err)
+	}
+
+	if err := svc.RemoveUnavailability(ctx, "u1", created.ID); err != nil {
+		t.Fatalf("RemoveUnavailability: %v", err)
+	}
+	err = svc.RemoveUnavailability(ctx, "u1", created.ID)
+	if !errors.As(err, &de) || de.Code != domain.ErrorCodeNotFound {
+		t.Fatalf("expected NOT_FOUND on second remove, got %v", err)
+	}
+
+	if len(events.events) != 2 || events.events[0].EventType() != "user.unavailability_added" || events.events[1].EventType() != "user.unavailability_removed" {
+		t.Fatalf("unexpected events %+v", events.events)
+	}
+}
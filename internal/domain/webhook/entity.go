@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+var SupportedEvents = []string{
+	"pr.created",
+	"pr.merged",
+	"pr.reassign",
+	"team.created",
+	"user.set_active",
+}
+
+const (
+	SignatureHeader      = "X-Webhook-Signature"
+	DefaultDeliveryLimit = 100
+
+	// DeliveryLease must outlast sending a whole batch; a claimed delivery
+	// whose result was never recorded is retried once it expires.
+	DeliveryLease = 5 * time.Minute
+)
+
+// TargetFilter rejects webhook hosts the service must not call.
+type TargetFilter func(host string) error
+
+// PublicTargets rejects loopback, link-local and unspecified hosts, so a
+// subscription cannot point the service at itself or at cloud metadata
+// endpoints. Host names are not resolved.
+func PublicTargets(host string) error {
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+		return errors.New("loopback webhook targets are not allowed")
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return nil
+	}
+	addr = addr.Unmap()
+	switch {
+	case addr.IsLoopback():
+		return errors.New("loopback webhook targets are not allowed")
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		return errors.New("link-local webhook targets are not allowed")
+	case addr.IsUnspecified():
+		return errors.New("unspecified webhook target address")
+	}
+	return nil
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+func (s DeliveryStatus) Valid() bool {
+	switch s {
+	case DeliveryPending, DeliveryDelivered, DeliveryFailed:
+		return true
+	}
+	return false
+}
+
+type Subscription struct {
+	ID         int64
+	URL        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+func (s Subscription) Accepts(eventType string) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+type Delivery struct {
+	ID             int64
+	SubscriptionID int64
+	Event          domain.Envelope
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+type DueDelivery struct {
+	Delivery     Delivery
+	Subscription Subscription
+}
+
+type DeliveryFilter struct {
+	SubscriptionID *int64
+	Status         *DeliveryStatus
+	Limit          int
+}
+
+type RetryPolicy = domain.RetryPolicy
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 6,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+	}
+}
+
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, s Subscription) (Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+	SubscriptionsFor(ctx context.Context, eventType string) ([]Subscription, error)
+
+	EnqueueDelivery(ctx context.Context, subscriptionID int64, env domain.Envelope) error
+	// ClaimDue returns up to limit due deliveries and pushes their
+	// next_attempt_at forward by lease so other dispatchers skip them.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]DueDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, responseStatus int) error
+	ScheduleRetry(ctx context.Context, id int64, responseStatus int, reason string, delay time.Duration) error
+	MarkFailed(ctx context.Context, id int64, responseStatus int, reason string) error
+	ListDeliveries(ctx context.Context, f DeliveryFilter) ([]Delivery, error)
+}
+
+type Sender interface {
+	Send(ctx context.Context, sub Subscription, d Delivery) (int, error)
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"prservice/internal/domain"
+)
+
+type Service interface {
+	Publish(ctx context.Context, env domain.Envelope) error
+	Subscribe(ctx context.Context, s Subscription) (Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	Unsubscribe(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, f DeliveryFilter) ([]Delivery, error)
+	DeliverDue(ctx context.Context, limit int) (int, error)
+}
+
+type service struct {
+	uow     domain.UnitOfWork
+	repo    Repository
+	sender  Sender
+	retry   RetryPolicy
+	targets TargetFilter
+}
+
+// NewService creates the webhook service; a nil targets filter defaults to
+// PublicTargets.
+func NewService(uow domain.UnitOfWork, repo Repository, sender Sender, retry RetryPolicy, targets TargetFilter) Service {
+	if targets == nil {
+		targets = PublicTargets
+	}
+	return &service{
+		uow:     uow,
+		repo:    repo,
+		sender:  sender,
+		retry:   retry,
+		targets: targets,
+	}
+}
+
+func (s *service) Publish(ctx context.Context, env domain.Envelope) error {
+	if !slices.Contains(SupportedEvents, env.Type) {
+		return nil
+	}
+
+	subs, err := s.repo.SubscriptionsFor(ctx, env.Type)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if err := s.repo.EnqueueDelivery(ctx, sub.ID, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) Subscribe(ctx context.Context, sub Subscription) (Subscription, error) {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Subscription{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "url must be an absolute http(s) URL",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if err := s.targets(u.Hostname()); err != nil {
+		return Subscription{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    err.Error(),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if strings.TrimSpace(sub.Secret) == "" {
+		return Subscription{}, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "secret is required",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	var types []string
+	for _, t := range sub.EventTypes {
+		if !slices.Contains(SupportedEvents, t) {
+			return Subscription{}, &domain.DomainError{
+				Code:       domain.ErrorCodeBadRequest,
+				Message:    "unsupported event type " + t,
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	sub.EventTypes = types
+
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+func (s *service) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+func (s *service) Unsubscribe(ctx context.Context, id int64) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *service) ListDeliveries(ctx context.Context, f DeliveryFilter) ([]Delivery, error) {
+	if f.Status != nil && !f.Status.Valid() {
+		return nil, &domain.DomainError{
+			Code:       domain.ErrorCodeBadRequest,
+			Message:    "invalid status",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultDeliveryLimit
+	}
+	return s.repo.ListDeliveries(ctx, f)
+}
+
+// DeliverDue claims a batch of due deliveries in a short transaction that
+// leases them until DeliveryLease expires, then sends them without holding
+// any locks and records each result on its own.
+func (s *service) DeliverDue(ctx context.Context, limit int) (int, error) {
+	var due []DueDelivery
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		due, err = s.repo.ClaimDue(ctx, limit, DeliveryLease)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, d := range due {
+		status, sendErr := s.sender.Send(ctx, d.Subscription, d.Delivery)
+		if sendErr == nil {
+			if err := s.repo.MarkDelivered(ctx, d.Delivery.ID, status); err != nil {
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+
+		attempt := d.Delivery.Attempts + 1
+		if attempt >= s.retry.MaxAttempts {
+			err = s.repo.MarkFailed(ctx, d.Delivery.ID, status, sendErr.Error())
+		} else {
+			err = s.repo.ScheduleRetry(ctx, d.Delivery.ID, status, sendErr.Error(), s.retry.Backoff(attempt))
+		}
+		if err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
@@ -0,0 +1,364 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"prservice/internal/domain"
+	"prservice/internal/domain/webhook"
+)
+
+type uowStub struct{}
+
+func (uowStub) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type txKey struct{}
+
+type uowTracker struct{}
+
+func (uowTracker) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+func inTx(ctx context.Context) bool {
+	v, _ := ctx.Value(txKey{}).(bool)
+	return v
+}
+
+type repoFake struct {
+	subs        []webhook.Subscription
+	deliveries  []webhook.Delivery
+	delays      []time.Duration
+	leases      []time.Duration
+	updatesInTx int
+}
+
+func (r *repoFake) CreateSubscription(ctx context.Context, s webhook.Subscription) (webhook.Subscription, error) {
+	s.ID = int64(len(r.subs) + 1)
+	r.subs = append(r.subs, s)
+	return s, nil
+}
+func (r *repoFake) ListSubscriptions(ctx context.Context) ([]webhook.Subscription, error) {
+	return append([]webhook.Subscription(nil), r.subs...), nil
+}
+func (r *repoFake) DeleteSubscription(ctx context.Context, id int64) error {
+	for i, s := range r.subs {
+		if s.ID == id {
+			r.subs = append(r.subs[:i], r.subs[i+1:]...)
+			return nil
+		}
+	}
+	return &domain.DomainError{Code: domain.ErrorCodeNotFound, Message: "webhook subscription not found", HTTPStatus: 404}
+}
+func (r *repoFake) SubscriptionsFor(ctx context.Context, eventType string) ([]webhook.Subscription, error) {
+	var res []webhook.Subscription
+	for _, s := range r.subs {
+		if s.Accepts(eventType) {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+func (r *repoFake) EnqueueDelivery(ctx context.Context, subscriptionID int64, env domain.Envelope) error {
+	for i, d := range r.deliveries {
+		if d.SubscriptionID == subscriptionID && d.Event.ID == env.ID {
+			if env.Replayed {
+				r.deliveries[i].Status = webhook.DeliveryPending
+				r.deliveries[i].Attempts = 0
+			}
+			return nil
+		}
+	}
+	r.deliveries = append(r.deliveries, webhook.Delivery{
+		ID:             int64(len(r.deliveries) + 1),
+		SubscriptionID: subscriptionID,
+		Event:          env,
+		Status:         webhook.DeliveryPending,
+	})
+	return nil
+}
+func (r *repoFake) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]webhook.DueDelivery, error) {
+	r.leases = append(r.leases, lease)
+	var res []webhook.DueDelivery
+	for _, d := range r.deliveries {
+		if d.Status != webhook.DeliveryPending || len(res) == limit {
+			continue
+		}
+		for _, s := range r.subs {
+			if s.ID == d.SubscriptionID {
+				res = append(res, webhook.DueDelivery{Delivery: d, Subscription: s})
+			}
+		}
+	}
+	return res, nil
+}
+func (r *repoFake) update(ctx context.Context, id int64, fn func(d *webhook.Delivery)) {
+	if inTx(ctx) {
+		r.updatesInTx++
+	}
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == id {
+			r.deliveries[i].Attempts++
+			fn(&r.deliveries[i])
+		}
+	}
+}
+func (r *repoFake) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	r.update(ctx, id, func(d *webhook.Delivery) {
+		d.Status = webhook.DeliveryDelivered
+		d.ResponseStatus = responseStatus
+	})
+	return nil
+}
+func (r *repoFake) ScheduleRetry(ctx context.Context, id int64, responseStatus int, reason string, delay time.Duration) error {
+	r.delays = append(r.delays, delay)
+	r.update(ctx, id, func(d *webhook.Delivery) {
+		d.ResponseStatus = responseStatus
+		d.LastError = reason
+	})
+	return nil
+}
+func (r *repoFake) MarkFailed(ctx context.Context, id int64, responseStatus int, reason string) error {
+	r.update(ctx, id, func(d *webhook.Delivery) {
+		d.Status = webhook.DeliveryFailed
+		d.ResponseStatus = responseStatus
+		d.LastError = reason
+	})
+	return nil
+}
+func (r *repoFake) ListDeliveries(ctx context.Context, f webhook.DeliveryFilter) ([]webhook.Delivery, error) {
+	return append([]webhook.Delivery(nil), r.deliveries...), nil
+}
+
+type senderFake struct {
+	statuses []int
+	sent     []webhook.DueDelivery
+	sentInTx int
+}
+
+func (s *senderFake) Send(ctx context.Context, sub webhook.Subscription, d webhook.Delivery) (int, error) {
+	if inTx(ctx) {
+		s.sentInTx++
+	}
+	s.sent = append(s.sent, webhook.DueDelivery{Delivery: d, Subscription: sub})
+	status := 200
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	if status >= 300 {
+		return status, errors.New("unexpected response status")
+	}
+	return status, nil
+}
+
+func TestSubscribe_Validation(t *testing.T) {
+	svc := webhook.NewService(uowStub{}, &repoFake{}, &senderFake{}, webhook.DefaultRetryPolicy(), nil)
+	ctx := context.Background()
+
+	cases := []webhook.Subscription{
+		{URL: "ftp://example.com/hook", Secret: "s"},
+		{URL: "/relative", Secret: "s"},
+		{URL: "http://example.com/hook", Secret: " "},
+		{URL: "http://example.com/hook", Secret: "s", EventTypes: []string{"pr.reviewed"}},
+		{URL: "http://localhost:8080/hook", Secret: "s"},
+		{URL: "http://127.0.0.1/hook", Secret: "s"},
+		{URL: "http://[::1]:9000/hook", Secret: "s"},
+		{URL: "http://[::ffff:127.0.0.1]/hook", Secret: "s"},
+		{URL: "http://169.254.169.254/latest/meta-data", Secret: "s"},
+		{URL: "http://[fe80::1%25eth0]/hook", Secret: "s"},
+		{URL: "http://0.0.0.0/hook", Secret: "s"},
+	}
+	for _, c := range cases {
+		_, err := svc.Subscribe(ctx, c)
+		var de *domain.DomainError
+		if !errors.As(err, &de) || de.Code != domain.ErrorCodeBadRequest {
+			t.Fatalf("expected BAD_REQUEST for %+v, got %v", c, err)
+		}
+	}
+
+	sub, err := svc.Subscribe(ctx, webhook.Subscription{
+		URL:        "https://example.com/hook",
+		Secret:     "s",
+		EventTypes: []string{"pr.created", "pr.created", "pr.merged"},
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if len(sub.EventTypes) != 2 {
+		t.Fatalf("expected duplicate event types to collapse, got %v", sub.EventTypes)
+	}
+}
+
+func TestPublish_FiltersByEventType(t *testing.T) {
+	repo := &repoFake{}
+	svc := webhook.NewService(uowStub{}, repo, &senderFake{}, webhook.DefaultRetryPolicy(), nil)
+	ctx := context.Background()
+
+	all, _ := svc.Subscribe(ctx, webhook.Subscription{URL: "http://a.example/hook", Secret: "a"})
+	merged, _ := svc.Subscribe(ctx, webhook.Subscription{URL: "http://b.example/hook", Secret: "b", EventTypes: []string{"pr.merged"}})
+
+	for i, typ := range []string{"pr.created", "pr.merged", "pr.reviewed"} {
+		env := domain.Envelope{ID: fmt.Sprintf("ev-%d", i), Type: typ, Version: 1, Payload: json.RawMessage(`{"pull_request_id":"pr-1"}`)}
+		if err := svc.Publish(ctx, env); err != nil {
+			t.Fatalf("publish %s: %v", typ, err)
+		}
+		if err := svc.Publish(ctx, env); err != nil {
+			t.Fatalf("republish %s: %v", typ, err)
+		}
+	}
+
+	var got []string
+	for _, d := range repo.deliveries {
+		if d.SubscriptionID == all.ID {
+			got = append(got, "all:"+d.Event.Type)
+		}
+		if d.SubscriptionID == merged.ID {
+			got = append(got, "merged:"+d.Event.Type)
+		}
+	}
+	want := []string{"all:pr.created", "all:pr.merged", "merged:pr.merged"}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected deliveries: %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected deliveries: %v", got)
+		}
+	}
+}
+
+func TestDeliverDue_RetriesWithBackoff(t *testing.T) {
+	repo := &repoFake{}
+	sender := &senderFake{statuses: []int{500, 502, 200}}
+	policy := webhook.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+	svc := webhook.NewService(uowStub{}, repo, sender, policy, nil)
+	ctx := context.Background()
+
+	_, _ = svc.Subscribe(ctx, webhook.Subscription{URL: "http://a.example/hook", Secret: "a"})
+	_ = svc.Publish(ctx, domain.Envelope{ID: "ev-1", Type: "team.created", Version: 1, Payload: json.RawMessage(`{"team_name":"backend"}`)})
+
+	for i, want := range []int{0, 0, 1} {
+		n, err := svc.DeliverDue(ctx, 10)
+		if err != nil {
+			t.Fatalf("deliver #%d: %v", i, err)
+		}
+		if n != want {
+			t.Fatalf("deliver #%d: expected %d delivered, got %d", i, want, n)
+		}
+	}
+
+	d := repo.deliveries[0]
+	if d.Status != webhook.DeliveryDelivered || d.Attempts != 3 || d.ResponseStatus != 200 {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+	if len(repo.delays) != 2 || repo.delays[0] != time.Second || repo.delays[1] != 2*time.Second {
+		t.Fatalf("unexpected backoff: %v", repo.delays)
+	}
+	if len(sender.sent) != 3 || sender.sent[0].Subscription.Secret != "a" {
+		t.Fatalf("unexpected sends: %+v", sender.sent)
+	}
+}
+
+func TestDeliverDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := &repoFake{}
+	sender := &senderFake{statuses: []int{500, 500, 500, 500}}
+	policy := webhook.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	svc := webhook.NewService(uowStub{}, repo, sender, policy, nil)
+	ctx := context.Background()
+
+	_, _ = svc.Subscribe(ctx, webhook.Subscription{URL: "http://a.example/hook", Secret: "a"})
+	_ = svc.Publish(ctx, domain.Envelope{ID: "ev-1", Type: "pr.merged", Version: 1})
+
+	for i := 0; i < 4; i++ {
+		if _, err := svc.DeliverDue(ctx, 10); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+	}
+
+	d := repo.deliveries[0]
+	if d.Status != webhook.DeliveryFailed || d.Attempts != 3 || d.LastError == "" {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+	if len(sender.sent) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(sender.sent))
+	}
+}
+
+func TestDeliverDue_SendsOutsideTransaction(t *testing.T) {
+	repo := &repoFake{}
+	sender := &senderFake{statuses: []int{200, 500}}
+	svc := webhook.NewService(uowTracker{}, repo, sender, webhook.DefaultRetryPolicy(), nil)
+	ctx := context.Background()
+
+	_, _ = svc.Subscribe(ctx, webhook.Subscription{URL: "http://a.example/hook", Secret: "a"})
+	_ = svc.Publish(ctx, domain.Envelope{ID: "ev-1", Type: "pr.created", Version: 1})
+	_ = svc.Publish(ctx, domain.Envelope{ID: "ev-2", Type: "pr.merged", Version: 1})
+
+	n, err := svc.DeliverDue(ctx, 10)
+	if err != nil || n != 1 {
+		t.Fatalf("deliver: %d %v", n, err)
+	}
+	if len(repo.leases) != 1 || repo.leases[0] != webhook.DeliveryLease {
+		t.Fatalf("expected deliveries to be claimed with a lease, got %v", repo.leases)
+	}
+	if len(sender.sent) != 2 || sender.sentInTx != 0 {
+		t.Fatalf("expected 2 sends outside a transaction, sent=%d in tx=%d", len(sender.sent), sender.sentInTx)
+	}
+	if repo.updatesInTx != 0 {
+		t.Fatalf("expected results to be recorded outside the claim transaction, got %d", repo.updatesInTx)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := webhook.RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	got := webhook.Sign("secret", []byte(`{"a":1}`))
+	if got != webhook.Sign("secret", []byte(`{"a":1}`)) || len(got) != len("sha256=")+64 {
+		t.Fatalf("unexpected signature %q", got)
+	}
+	if got == webhook.Sign("other", []byte(`{"a":1}`)) {
+		t.Fatalf("signature must depend on secret")
+	}
+}
+
+func TestPublish_ReplayRedelivers(t *testing.T) {
+	repo := &repoFake{}
+	sender := &senderFake{}
+	svc := webhook.NewService(uowStub{}, repo, sender, webhook.DefaultRetryPolicy(), nil)
+	ctx := context.Background()
+
+	_, _ = svc.Subscribe(ctx, webhook.Subscription{URL: "http://a.example/hook", Secret: "a"})
+	env := domain.Envelope{ID: "ev-1", Type: "pr.created", Version: 1}
+	_ = svc.Publish(ctx, env)
+	if n, err := svc.DeliverDue(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("deliver: %d %v", n, err)
+	}
+
+	_ = svc.Publish(ctx, env)
+	if n, _ := svc.DeliverDue(ctx, 10); n != 0 {
+		t.Fatalf("duplicate event must not be redelivered")
+	}
+
+	env.Replayed = true
+	_ = svc.Publish(ctx, env)
+	if n, err := svc.DeliverDue(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("replayed event must be redelivered: %d %v", n, err)
+	}
+	if len(repo.deliveries) != 1 || len(sender.sent) != 2 {
+		t.Fatalf("unexpected deliveries %+v, sent %d", repo.deliveries, len(sender.sent))
+	}
+}
@@ -2,6 +2,7 @@ package async
 
 import (
 	"context"
+	"sync"
 
 	"go.uber.org/zap"
 
@@ -11,24 +12,79 @@ import (
 type AsyncEventBus struct {
 	pool *WorkerPool
 	log  *zap.Logger
+
+	mu          sync.Mutex
+	subscribers map[chan domain.Envelope]struct{}
 }
 
 func NewAsyncEventBus(ctx context.Context, poolSize int, log *zap.Logger) *AsyncEventBus {
 	return &AsyncEventBus{
-		pool: NewWorkerPool(ctx, poolSize, log),
-		log:  log,
+		pool:        NewWorkerPool(ctx, poolSize, log),
+		log:         log,
+		subscribers: map[chan domain.Envelope]struct{}{},
 	}
 }
 
-func (b *AsyncEventBus) Publish(ctx context.Context, e domain.Event) {
-	b.pool.Submit(func(_ context.Context) {
+func (b *AsyncEventBus) Publish(ctx context.Context, env domain.Envelope) error {
+	if !env.Replayed {
+		b.broadcast(env)
+	}
+	return b.pool.Submit(func(_ context.Context) {
 		b.log.Info("domain_event",
-			zap.String("type", e.Type),
-			zap.Any("payload", e.Payload),
+			zap.String("id", env.ID),
+			zap.String("type", env.Type),
+			zap.Int("version", env.Version),
+			zap.String("actor", env.Actor),
+			zap.Time("occurred_at", env.OccurredAt),
+			zap.ByteString("payload", env.Payload),
 		)
 	})
 }
 
+func (b *AsyncEventBus) Subscribe(buffer int) (<-chan domain.Envelope, func()) {
+	ch := make(chan domain.Envelope, buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.drop(ch)
+	}
+}
+
+func (b *AsyncEventBus) broadcast(env domain.Envelope) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- env:
+		default:
+			b.log.Warn("event stream subscriber is too slow, disconnecting", zap.String("event_id", env.ID))
+			b.drop(ch)
+		}
+	}
+}
+
+func (b *AsyncEventBus) drop(ch chan domain.Envelope) {
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (b *AsyncEventBus) CloseSubscriptions() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		b.drop(ch)
+	}
+}
+
 func (b *AsyncEventBus) Close() {
+	b.CloseSubscriptions()
 	b.pool.Shutdown()
 }
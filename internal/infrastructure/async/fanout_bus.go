@@ -0,0 +1,18 @@
+package async
+
+import (
+	"context"
+
+	"prservice/internal/domain"
+)
+
+type FanoutBus []domain.EventBus
+
+func (f FanoutBus) Publish(ctx context.Context, env domain.Envelope) error {
+	for _, b := range f {
+		if err := b.Publish(ctx, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package async
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"prservice/internal/domain"
+)
+
+func DefaultOutboxRetryPolicy() domain.RetryPolicy {
+	return domain.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+	}
+}
+
+type OutboxRelay struct {
+	uow      domain.UnitOfWork
+	store    domain.OutboxStore
+	target   domain.EventBus
+	live     domain.EventBus
+	retry    domain.RetryPolicy
+	interval time.Duration
+	batch    int
+	log      *zap.Logger
+
+	poller
+}
+
+func NewOutboxRelay(
+	uow domain.UnitOfWork,
+	store domain.OutboxStore,
+	target domain.EventBus,
+	live domain.EventBus,
+	retry domain.RetryPolicy,
+	interval time.Duration,
+	batch int,
+	log *zap.Logger,
+) *OutboxRelay {
+	return &OutboxRelay{
+		uow:      uow,
+		store:    store,
+		target:   target,
+		live:     live,
+		retry:    retry,
+		interval: interval,
+		batch:    batch,
+		log:      log,
+	}
+}
+
+func (r *OutboxRelay) Start(ctx context.Context) {
+	r.start(ctx, r.interval, r.batch, r.log, "outbox dispatch failed", r.DispatchPending)
+}
+
+// DispatchPending delivers up to batch messages, each in its own
+// transaction, so a failing subscriber only rolls back its own message.
+// target runs inside that transaction; live only sees a message once it
+// has committed, so live consumers never get ahead of the event log.
+func (r *OutboxRelay) DispatchPending(ctx context.Context) (int, error) {
+	dispatched := 0
+	for i := 0; i < r.batch; i++ {
+		found, ok, err := r.dispatchNext(ctx)
+		if err != nil {
+			return dispatched, err
+		}
+		if !found {
+			break
+		}
+		if ok {
+			dispatched++
+		}
+	}
+	return dispatched, nil
+}
+
+func (r *OutboxRelay) dispatchNext(ctx context.Context) (found, ok bool, err error) {
+	var (
+		m      domain.OutboxMessage
+		pubErr error
+	)
+
+	err = r.uow.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		m, found, err = r.store.ClaimNext(ctx)
+		if err != nil || !found {
+			return err
+		}
+		if pubErr = r.target.Publish(ctx, m.Envelope); pubErr != nil {
+			return pubErr
+		}
+		return r.store.MarkDispatched(ctx, m.ID)
+	})
+	if pubErr != nil {
+		return true, false, r.fail(ctx, m, pubErr)
+	}
+	if err != nil || !found {
+		return found, false, err
+	}
+
+	if err := r.live.Publish(ctx, m.Envelope); err != nil {
+		r.log.Warn("live event publish failed",
+			zap.String("event_id", m.Envelope.ID),
+			zap.String("type", m.Envelope.Type),
+			zap.Error(err),
+		)
+	}
+	return true, true, nil
+}
+
+func (r *OutboxRelay) fail(ctx context.Context, m domain.OutboxMessage, cause error) error {
+	attempt := m.Attempts + 1
+	fields := []zap.Field{
+		zap.Int64("id", m.ID),
+		zap.String("event_id", m.Envelope.ID),
+		zap.String("type", m.Envelope.Type),
+		zap.Int("attempt", attempt),
+		zap.Error(cause),
+	}
+
+	if attempt >= r.retry.MaxAttempts {
+		r.log.Error("outbox delivery failed, moved to dead letter", fields...)
+		return r.store.MarkDead(ctx, m.ID, cause.Error())
+	}
+	r.log.Warn("outbox delivery failed", fields...)
+	return r.store.ScheduleRetry(ctx, m.ID, cause.Error(), r.retry.Backoff(attempt))
+}
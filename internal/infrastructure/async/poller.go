@@ -0,0 +1,57 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type poller struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (p *poller) start(
+	parent context.Context,
+	interval time.Duration,
+	batch int,
+	log *zap.Logger,
+	msg string,
+	step func(ctx context.Context) (int, error),
+) {
+	ctx, cancel := context.WithCancel(parent)
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			n, err := step(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Error(msg, zap.Error(err))
+			}
+			if n == batch && err == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *poller) Close() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
@@ -0,0 +1,36 @@
+package async
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"prservice/internal/domain/webhook"
+)
+
+type WebhookDispatcher struct {
+	svc      webhook.Service
+	interval time.Duration
+	batch    int
+	log      *zap.Logger
+
+	poller
+}
+
+func NewWebhookDispatcher(svc webhook.Service, interval time.Duration, batch int, log *zap.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		svc:      svc,
+		interval: interval,
+		batch:    batch,
+		log:      log,
+	}
+}
+
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	d.start(ctx, d.interval, d.batch, d.log, "webhook delivery failed", d.DeliverDue)
+}
+
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	return d.svc.DeliverDue(ctx, d.batch)
+}
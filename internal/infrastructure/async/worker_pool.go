@@ -61,11 +61,12 @@ func (p *WorkerPool) worker(id int) {
 	}
 }
 
-func (p *WorkerPool) Submit(task Task) {
+func (p *WorkerPool) Submit(task Task) error {
 	select {
 	case <-p.ctx.Done():
-		return
+		return p.ctx.Err()
 	case p.tasks <- task:
+		return nil
 	}
 }
 
@@ -0,0 +1,110 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"prservice/internal/domain"
+	"prservice/internal/domain/eventlog"
+)
+
+type EventLogRepository struct {
+	db *sql.DB
+}
+
+func NewEventLogRepository(db *sql.DB) *EventLogRepository {
+	return &EventLogRepository{db: db}
+}
+
+func (r *EventLogRepository) Append(ctx context.Context, env domain.Envelope) error {
+	_, err := exec(ctx, r.db,
+		`INSERT INTO events (event_id, event_type, event_version, occurred_at, actor, payload)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (event_id) DO NOTHING`,
+		env.ID, env.Type, env.Version, env.OccurredAt, env.Actor, string(env.Payload),
+	)
+	return err
+}
+
+func (r *EventLogRepository) SeqOf(ctx context.Context, eventID string) (int64, error) {
+	var seq int64
+	err := queryRow(ctx, r.db, `SELECT seq FROM events WHERE event_id = $1`, eventID).Scan(&seq)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "event not found",
+			HTTPStatus: 404,
+		}
+	}
+	return seq, err
+}
+
+func (r *EventLogRepository) List(ctx context.Context, f eventlog.Filter) ([]eventlog.Record, error) {
+	var since, until, typ, prID any
+	if f.Since != nil {
+		since = *f.Since
+	}
+	if f.Until != nil {
+		until = *f.Until
+	}
+	if f.Type != "" {
+		typ = f.Type
+	}
+	if f.PullRequestID != "" {
+		prID = f.PullRequestID
+	}
+
+	rows, err := query(ctx, r.db,
+		`SELECT seq, event_id, event_type, event_version, occurred_at, actor, payload
+		   FROM events
+		  WHERE seq > $1
+		    AND ($2::bigint = 0 OR seq <= $2::bigint)
+		    AND ($3::timestamptz IS NULL OR occurred_at >= $3::timestamptz)
+		    AND ($4::timestamptz IS NULL OR occurred_at < $4::timestamptz)
+		    AND ($5::text IS NULL OR event_type = $5::text)
+		    AND ($6::text IS NULL OR pull_request_id = $6::text)
+		    AND (cardinality($7::text[]) = 0 OR event_type = ANY($7::text[]))
+		  ORDER BY seq
+		  LIMIT $8`,
+		f.AfterSeq, f.ToSeq, since, until, typ, prID, stringList(f.Types), f.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []eventlog.Record
+	for rows.Next() {
+		var rec eventlog.Record
+		env := &rec.Envelope
+		var payload []byte
+		if err := rows.Scan(&rec.Seq, &env.ID, &env.Type, &env.Version, &env.OccurredAt, &env.Actor, &payload); err != nil {
+			return nil, err
+		}
+		env.Payload = payload
+		res = append(res, rec)
+	}
+	return res, rows.Err()
+}
+
+func (r *EventLogRepository) PullRequestScope(ctx context.Context, pullRequestID string) (eventlog.Scope, error) {
+	scope := eventlog.Scope{PullRequestID: pullRequestID}
+	err := queryRow(ctx, r.db,
+		`SELECT p.author_id, u.team_name,
+		        COALESCE(ARRAY(
+		            SELECT rv.user_id
+		              FROM pull_request_reviewers rv
+		             WHERE rv.pull_request_id = p.pull_request_id
+		             ORDER BY rv.user_id
+		        ), '{}')
+		   FROM pull_requests p
+		   JOIN users u ON u.user_id = p.author_id
+		  WHERE p.pull_request_id = $1`,
+		pullRequestID,
+	).Scan(&scope.AuthorID, &scope.TeamName, textArray(&scope.Reviewers))
+	if errors.Is(err, sql.ErrNoRows) {
+		return scope, nil
+	}
+	return scope, err
+}
@@ -0,0 +1,135 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type Outbox struct {
+	db *sql.DB
+}
+
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+func (o *Outbox) Publish(ctx context.Context, e domain.Event) error {
+	env, err := domain.NewEnvelope(ctx, e)
+	if err != nil {
+		return err
+	}
+	_, err = exec(ctx, o.db,
+		`INSERT INTO event_outbox (event_id, event_type, event_version, occurred_at, actor, payload, aggregate)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		env.ID, env.Type, env.Version, env.OccurredAt, env.Actor, string(env.Payload), domain.AggregateOf(env),
+	)
+	return err
+}
+
+// PublishAll stores events with one multi-row insert; ids follow the order
+// of events, so the relay delivers them in that order.
+func (o *Outbox) PublishAll(ctx context.Context, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var ids, types, actors, payloads, aggregates []string
+	var versions []int64
+	var occurred []time.Time
+	for _, e := range events {
+		env, err := domain.NewEnvelope(ctx, e)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, env.ID)
+		types = append(types, env.Type)
+		versions = append(versions, int64(env.Version))
+		occurred = append(occurred, env.OccurredAt)
+		actors = append(actors, env.Actor)
+		payloads = append(payloads, string(env.Payload))
+		aggregates = append(aggregates, domain.AggregateOf(env))
+	}
+
+	_, err := exec(ctx, o.db,
+		`INSERT INTO event_outbox (event_id, event_type, event_version, occurred_at, actor, payload, aggregate)
+		 SELECT event_id, event_type, event_version, occurred_at, actor, payload::jsonb, aggregate
+		   FROM unnest($1::text[], $2::text[], $3::int[], $4::timestamptz[], $5::text[], $6::text[], $7::text[])
+		        WITH ORDINALITY AS e(event_id, event_type, event_version, occurred_at, actor, payload, aggregate, n)
+		  ORDER BY n`,
+		ids, types, versions, occurred, actors, payloads, aggregates,
+	)
+	return err
+}
+
+func (o *Outbox) ClaimNext(ctx context.Context) (domain.OutboxMessage, bool, error) {
+	var m domain.OutboxMessage
+	env := &m.Envelope
+	var payload []byte
+
+	err := queryRow(ctx, o.db,
+		`SELECT o.id, o.event_id, o.event_type, o.event_version, o.occurred_at, o.actor, o.payload, o.attempts
+		   FROM event_outbox o
+		  WHERE o.dispatched_at IS NULL
+		    AND o.dead_at IS NULL
+		    AND o.next_attempt_at <= NOW()
+		    AND NOT EXISTS (
+		            SELECT 1
+		              FROM event_outbox p
+		             WHERE p.aggregate = o.aggregate
+		               AND p.dispatched_at IS NULL
+		               AND p.dead_at IS NULL
+		               AND p.id < o.id
+		        )
+		  ORDER BY o.id
+		  LIMIT 1
+		  FOR UPDATE SKIP LOCKED`,
+	).Scan(&m.ID, &env.ID, &env.Type, &env.Version, &env.OccurredAt, &env.Actor, &payload, &m.Attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.OutboxMessage{}, false, nil
+	}
+	if err != nil {
+		return domain.OutboxMessage{}, false, err
+	}
+	env.Payload = payload
+	return m, true, nil
+}
+
+func (o *Outbox) MarkDispatched(ctx context.Context, id int64) error {
+	_, err := exec(ctx, o.db,
+		`UPDATE event_outbox
+		    SET dispatched_at = NOW(),
+		        attempts = attempts + 1,
+		        last_error = NULL
+		  WHERE id = $1`,
+		id,
+	)
+	return err
+}
+
+func (o *Outbox) ScheduleRetry(ctx context.Context, id int64, reason string, delay time.Duration) error {
+	_, err := exec(ctx, o.db,
+		`UPDATE event_outbox
+		    SET attempts = attempts + 1,
+		        last_error = $2,
+		        next_attempt_at = NOW() + $3 * INTERVAL '1 millisecond'
+		  WHERE id = $1`,
+		id, reason, delay.Milliseconds(),
+	)
+	return err
+}
+
+func (o *Outbox) MarkDead(ctx context.Context, id int64, reason string) error {
+	_, err := exec(ctx, o.db,
+		`UPDATE event_outbox
+		    SET attempts = attempts + 1,
+		        last_error = $2,
+		        dead_at = NOW()
+		  WHERE id = $1`,
+		id, reason,
+	)
+	return err
+}
@@ -3,6 +3,7 @@ package pg
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 
 	"prservice/internal/domain"
@@ -35,10 +36,10 @@ func (r *PRRepository) CreateWithReviewers(ctx context.Context, p pr.PullRequest
 
 	var createdAt sql.NullTime
 	if err := queryRow(ctx, r.db,
-		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status)
-		 VALUES ($1, $2, $3, $4)
+		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, changed_files, labels)
+		 VALUES ($1, $2, $3, $4, $5, $6)
 		 RETURNING created_at`,
-		p.ID, p.Name, p.AuthorID, string(p.Status),
+		p.ID, p.Name, p.AuthorID, string(p.Status), stringList(p.Files), stringList(p.Labels),
 	).Scan(&createdAt); err != nil {
 		return pr.PullRequest{}, err
 	}
@@ -49,8 +50,10 @@ func (r *PRRepository) CreateWithReviewers(ctx context.Context, p pr.PullRequest
 
 	for _, uid := range p.AssignedReviewers {
 		if _, err := exec(ctx, r.db,
-			`INSERT INTO pull_request_reviewers (pull_request_id, user_id)
-			 VALUES ($1, $2)`,
+			`INSERT INTO pull_request_reviewers (pull_request_id, user_id, source_team)
+			 SELECT $1, user_id, team_name
+			   FROM users
+			  WHERE user_id = $2`,
 			p.ID, uid,
 		); err != nil {
 			return pr.PullRequest{}, err
@@ -59,18 +62,93 @@ func (r *PRRepository) CreateWithReviewers(ctx context.Context, p pr.PullRequest
 	return p, nil
 }
 
+const prColumns = `pull_request_name, author_id, status, created_at, merged_at, closed_at, merge_forced, changed_files, labels`
+
+func (r *PRRepository) GetByID(ctx context.Context, id string) (pr.PullRequest, error) {
+	return r.getByID(ctx, id, "")
+}
+
 func (r *PRRepository) LockByID(ctx context.Context, id string) (pr.PullRequest, error) {
-	var p pr.PullRequest
-	var status string
-	var createdAt, mergedAt sql.NullTime
+	return r.getByID(ctx, id, " FOR UPDATE")
+}
 
-	err := queryRow(ctx, r.db,
-		`SELECT pull_request_name, author_id, status, created_at, merged_at
+// LockOpenByReviewers locks, in id order, every OPEN PR reviewed by one of
+// userIDs and loads its reviewers in a second query.
+func (r *PRRepository) LockOpenByReviewers(ctx context.Context, userIDs []string) ([]pr.PullRequest, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := query(ctx, r.db,
+		`SELECT pull_request_id, `+prColumns+`
 		   FROM pull_requests
-		  WHERE pull_request_id = $1
-		  FOR UPDATE`,
+		  WHERE status = 'OPEN'
+		    AND pull_request_id IN (
+		        SELECT pull_request_id
+		          FROM pull_request_reviewers
+		         WHERE user_id = ANY($1))
+		  ORDER BY pull_request_id
+		    FOR UPDATE`,
+		userIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []pr.PullRequest
+	index := map[string]int{}
+	for rows.Next() {
+		var id string
+		p, err := scanPRColumns(rows, &id)
+		if err != nil {
+			return nil, err
+		}
+		p.ID = id
+		index[p.ID] = len(res)
+		res = append(res, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(res))
+	for _, p := range res {
+		ids = append(ids, p.ID)
+	}
+	reviewers, err := query(ctx, r.db,
+		`SELECT pull_request_id, user_id
+		   FROM pull_request_reviewers
+		  WHERE pull_request_id = ANY($1)
+		  ORDER BY pull_request_id, user_id`,
+		ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer reviewers.Close()
+
+	for reviewers.Next() {
+		var prID, userID string
+		if err := reviewers.Scan(&prID, &userID); err != nil {
+			return nil, err
+		}
+		p := &res[index[prID]]
+		p.AssignedReviewers = append(p.AssignedReviewers, userID)
+	}
+	return res, reviewers.Err()
+}
+
+func (r *PRRepository) getByID(ctx context.Context, id, suffix string) (pr.PullRequest, error) {
+	p, err := scanPR(id, queryRow(ctx, r.db,
+		`SELECT `+prColumns+`
+		   FROM pull_requests
+		  WHERE pull_request_id = $1`+suffix,
 		id,
-	).Scan(&p.Name, &p.AuthorID, &status, &createdAt, &mergedAt)
+	))
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return pr.PullRequest{}, &domain.DomainError{
@@ -79,43 +157,52 @@ func (r *PRRepository) LockByID(ctx context.Context, id string) (pr.PullRequest,
 			HTTPStatus: 404,
 		}
 	}
+	return p, err
+}
+
+func (r *PRRepository) UpdateStatusMerged(ctx context.Context, id string, forced bool) (pr.PullRequest, error) {
+	return scanPR(id, queryRow(ctx, r.db,
+		`UPDATE pull_requests
+		   SET status = 'MERGED',
+		       merged_at = NOW(),
+		       merge_forced = $2
+		 WHERE pull_request_id = $1
+		 RETURNING `+prColumns,
+		id, forced,
+	))
+}
+
+func (r *PRRepository) UpdateStatus(ctx context.Context, id string, status pr.Status) (pr.PullRequest, error) {
+	return scanPR(id, queryRow(ctx, r.db,
+		`UPDATE pull_requests
+		   SET status = $2,
+		       closed_at = CASE WHEN $2 = 'CLOSED' THEN NOW() END
+		 WHERE pull_request_id = $1
+		 RETURNING `+prColumns,
+		id, string(status),
+	))
+}
+
+func scanPR(id string, row *sql.Row) (pr.PullRequest, error) {
+	p, err := scanPRColumns(row)
 	if err != nil {
 		return pr.PullRequest{}, err
 	}
-
 	p.ID = id
-	p.Status = pr.Status(status)
-	if createdAt.Valid {
-		t := createdAt.Time
-		p.CreatedAt = &t
-	}
-	if mergedAt.Valid {
-		t := mergedAt.Time
-		p.MergedAt = &t
-	}
-
 	return p, nil
 }
 
-func (r *PRRepository) UpdateStatusMerged(ctx context.Context, id string) (pr.PullRequest, error) {
+// scanPRColumns scans prColumns after the optional lead destinations.
+func scanPRColumns(row interface{ Scan(dest ...any) error }, lead ...any) (pr.PullRequest, error) {
 	var p pr.PullRequest
 	var status string
-	var createdAt, mergedAt sql.NullTime
-
-	err := queryRow(ctx, r.db,
-		`UPDATE pull_requests
-		   SET status = 'MERGED',
-		       merged_at = NOW()
-		 WHERE pull_request_id = $1
-		 RETURNING pull_request_name, author_id, status, created_at, merged_at`,
-		id,
-	).Scan(&p.Name, &p.AuthorID, &status, &createdAt, &mergedAt)
+	var createdAt, mergedAt, closedAt sql.NullTime
 
-	if err != nil {
+	if err := row.Scan(append(lead, &p.Name, &p.AuthorID, &status, &createdAt, &mergedAt, &closedAt, &p.MergeForced,
+		textArray(&p.Files), textArray(&p.Labels))...); err != nil {
 		return pr.PullRequest{}, err
 	}
 
-	p.ID = id
 	p.Status = pr.Status(status)
 	if createdAt.Valid {
 		t := createdAt.Time
@@ -125,6 +212,10 @@ func (r *PRRepository) UpdateStatusMerged(ctx context.Context, id string) (pr.Pu
 		t := mergedAt.Time
 		p.MergedAt = &t
 	}
+	if closedAt.Valid {
+		t := closedAt.Time
+		p.ClosedAt = &t
+	}
 
 	return p, nil
 }
@@ -155,15 +246,20 @@ func (r *PRRepository) GetReviewers(ctx context.Context, prID string) ([]string,
 
 func (r *PRRepository) SetReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
 	if _, err := exec(ctx, r.db,
-		`DELETE FROM pull_request_reviewers WHERE pull_request_id = $1`,
-		prID,
+		`DELETE FROM pull_request_reviewers
+		  WHERE pull_request_id = $1
+		    AND NOT (user_id = ANY($2))`,
+		prID, append([]string{}, reviewerIDs...),
 	); err != nil {
 		return err
 	}
 	for _, uid := range reviewerIDs {
 		if _, err := exec(ctx, r.db,
-			`INSERT INTO pull_request_reviewers (pull_request_id, user_id)
-			 VALUES ($1, $2)`,
+			`INSERT INTO pull_request_reviewers (pull_request_id, user_id, source_team)
+			 SELECT $1, user_id, team_name
+			   FROM users
+			  WHERE user_id = $2
+			 ON CONFLICT (pull_request_id, user_id) DO NOTHING`,
 			prID, uid,
 		); err != nil {
 			return err
@@ -172,6 +268,40 @@ func (r *PRRepository) SetReviewers(ctx context.Context, prID string, reviewerID
 	return nil
 }
 
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, changes []pr.ReviewerChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, 0, len(changes))
+	oldIDs := make([]string, 0, len(changes))
+	newIDs := make([]string, 0, len(changes))
+	for _, c := range changes {
+		prIDs = append(prIDs, c.PRID)
+		oldIDs = append(oldIDs, c.OldUserID)
+		newIDs = append(newIDs, c.NewUserID)
+	}
+
+	if _, err := exec(ctx, r.db,
+		`DELETE FROM pull_request_reviewers prr
+		  USING unnest($1::text[], $2::text[]) AS c(pull_request_id, user_id)
+		  WHERE prr.pull_request_id = c.pull_request_id
+		    AND prr.user_id = c.user_id`,
+		prIDs, oldIDs,
+	); err != nil {
+		return err
+	}
+	_, err := exec(ctx, r.db,
+		`INSERT INTO pull_request_reviewers (pull_request_id, user_id, source_team)
+		 SELECT c.pull_request_id, u.user_id, u.team_name
+		   FROM unnest($1::text[], $2::text[]) AS c(pull_request_id, user_id)
+		   JOIN users u ON u.user_id = c.user_id
+		 ON CONFLICT (pull_request_id, user_id) DO NOTHING`,
+		prIDs, newIDs,
+	)
+	return err
+}
+
 func (r *PRRepository) UserIsReviewer(ctx context.Context, prID, userID string) (bool, error) {
 	var exists bool
 	err := queryRow(ctx, r.db,
@@ -214,3 +344,270 @@ func (r *PRRepository) GetUserPRs(ctx context.Context, userID string) ([]pr.Pull
 	}
 	return res, rows.Err()
 }
+
+func (r *PRRepository) GetReviews(ctx context.Context, prID string) ([]pr.Review, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT user_id, state, comment, assigned_at, updated_at, COALESCE(source_team, '')
+		   FROM pull_request_reviewers
+		  WHERE pull_request_id = $1
+		  ORDER BY user_id`,
+		prID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []pr.Review
+	for rows.Next() {
+		var rv pr.Review
+		var state string
+		var comment sql.NullString
+		var assignedAt, updatedAt sql.NullTime
+		if err := rows.Scan(&rv.ReviewerID, &state, &comment, &assignedAt, &updatedAt, &rv.SourceTeam); err != nil {
+			return nil, err
+		}
+		rv.State = pr.ReviewState(state)
+		rv.Comment = comment.String
+		if assignedAt.Valid {
+			t := assignedAt.Time
+			rv.AssignedAt = &t
+		}
+		if updatedAt.Valid {
+			t := updatedAt.Time
+			rv.UpdatedAt = &t
+		}
+		res = append(res, rv)
+	}
+	return res, rows.Err()
+}
+
+func (r *PRRepository) SetReviewState(ctx context.Context, prID, userID string, state pr.ReviewState, comment string) error {
+	res, err := exec(ctx, r.db,
+		`UPDATE pull_request_reviewers
+		    SET state = $3,
+		        comment = COALESCE(NULLIF($4, ''), comment),
+		        updated_at = NOW()
+		  WHERE pull_request_id = $1
+		    AND user_id = $2`,
+		prID, userID, string(state), comment,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeNotAssigned,
+			Message:    "reviewer is not assigned to this PR",
+			HTTPStatus: 409,
+		}
+	}
+	return nil
+}
+
+func (r *PRRepository) RecordDecline(ctx context.Context, d pr.Decline) error {
+	_, err := exec(ctx, r.db,
+		`INSERT INTO review_declines (pull_request_id, user_id, replaced_by, reason)
+		 VALUES ($1, $2, NULLIF($3, ''), $4)`,
+		d.PRID, d.UserID, d.ReplacedBy, d.Reason,
+	)
+	return err
+}
+
+func (r *PRRepository) GetDeclinedUsers(ctx context.Context, prID string) ([]string, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT DISTINCT user_id
+		   FROM review_declines
+		  WHERE pull_request_id = $1`,
+		prID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+	return res, rows.Err()
+}
+
+func (r *PRRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	res := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+
+	rows, err := query(ctx, r.db,
+		`SELECT prr.user_id, COUNT(*)
+		   FROM pull_request_reviewers prr
+		   JOIN pull_requests pr
+		     ON pr.pull_request_id = prr.pull_request_id
+		  WHERE pr.status = 'OPEN'
+		    AND prr.user_id = ANY($1)
+		  GROUP BY prr.user_id`,
+		userIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		res[id] = n
+	}
+	return res, rows.Err()
+}
+
+func (r *PRRepository) RecentReviewers(ctx context.Context, authorID string, limit int) ([][]string, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT recent.pull_request_id, prr.user_id
+		   FROM (
+		         SELECT pull_request_id, created_at
+		           FROM pull_requests
+		          WHERE author_id = $1
+		          ORDER BY created_at DESC, pull_request_id DESC
+		          LIMIT $2
+		        ) recent
+		   LEFT JOIN pull_request_reviewers prr
+		     ON prr.pull_request_id = recent.pull_request_id
+		  ORDER BY recent.created_at DESC, recent.pull_request_id DESC, prr.user_id`,
+		authorID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res [][]string
+	last := ""
+	for rows.Next() {
+		var prID string
+		var userID sql.NullString
+		if err := rows.Scan(&prID, &userID); err != nil {
+			return nil, err
+		}
+		if len(res) == 0 || prID != last {
+			res = append(res, nil)
+			last = prID
+		}
+		if userID.Valid {
+			res[len(res)-1] = append(res[len(res)-1], userID.String)
+		}
+	}
+	return res, rows.Err()
+}
+
+type decisionRecord struct {
+	Purpose    string         `json:"purpose,omitempty"`
+	TeamName   string         `json:"team_name"`
+	Strategy   string         `json:"strategy"`
+	Seed       int64          `json:"seed"`
+	Candidates []string       `json:"candidates"`
+	Max        int            `json:"max"`
+	Scores     map[string]int `json:"scores,omitempty"`
+	Selected   []string       `json:"selected"`
+}
+
+func (r *PRRepository) RecordAssignment(ctx context.Context, a pr.Assignment) error {
+	return r.RecordAssignments(ctx, []pr.Assignment{a})
+}
+
+func (r *PRRepository) RecordAssignments(ctx context.Context, assignments []pr.Assignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, 0, len(assignments))
+	operations := make([]string, 0, len(assignments))
+	decisions := make([]string, 0, len(assignments))
+	for _, a := range assignments {
+		records := make([]decisionRecord, 0, len(a.Decisions))
+		for _, d := range a.Decisions {
+			records = append(records, decisionRecord{
+				Purpose:    d.Purpose,
+				TeamName:   d.TeamName,
+				Strategy:   string(d.Strategy),
+				Seed:       d.Seed,
+				Candidates: d.Candidates,
+				Max:        d.Max,
+				Scores:     d.Scores,
+				Selected:   d.Selected,
+			})
+		}
+		data, err := json.Marshal(records)
+		if err != nil {
+			return err
+		}
+		prIDs = append(prIDs, a.PRID)
+		operations = append(operations, a.Operation)
+		decisions = append(decisions, string(data))
+	}
+
+	_, err := exec(ctx, r.db,
+		`INSERT INTO pull_request_assignments (pull_request_id, operation, decisions)
+		 SELECT pull_request_id, operation, decisions::jsonb
+		   FROM unnest($1::text[], $2::text[], $3::text[]) WITH ORDINALITY
+		        AS a(pull_request_id, operation, decisions, n)
+		  ORDER BY n`,
+		prIDs, operations, decisions,
+	)
+	return err
+}
+
+func (r *PRRepository) GetAssignments(ctx context.Context, prID string) ([]pr.Assignment, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT id, operation, decisions, created_at
+		   FROM pull_request_assignments
+		  WHERE pull_request_id = $1
+		  ORDER BY id`,
+		prID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []pr.Assignment
+	for rows.Next() {
+		a := pr.Assignment{PRID: prID}
+		var data []byte
+		if err := rows.Scan(&a.ID, &a.Operation, &data, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		var records []decisionRecord
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, err
+		}
+		for _, d := range records {
+			a.Decisions = append(a.Decisions, pr.Decision{
+				Purpose:    d.Purpose,
+				TeamName:   d.TeamName,
+				Strategy:   pr.Strategy(d.Strategy),
+				Seed:       d.Seed,
+				Candidates: d.Candidates,
+				Max:        d.Max,
+				Scores:     d.Scores,
+				Selected:   d.Selected,
+			})
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
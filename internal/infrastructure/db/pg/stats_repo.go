@@ -19,7 +19,8 @@ func (r *StatsRepository) GetUserAssignmentStats(ctx context.Context, teamName *
 	const q = `
 	SELECT u.user_id, COUNT(prr.pull_request_id) AS assigned_total, 
 	COUNT(prr.pull_request_id) FILTER (WHERE pr.status = 'OPEN') AS assigned_open,
-    COUNT(prr.pull_request_id) FILTER (WHERE pr.status = 'MERGED') AS assigned_merged
+    COUNT(prr.pull_request_id) FILTER (WHERE pr.status = 'MERGED') AS assigned_merged,
+	(SELECT COUNT(*) FROM review_declines d WHERE d.user_id = u.user_id) AS declined
 	FROM users u LEFT JOIN pull_request_reviewers prr ON u.user_id = prr.user_id
 	LEFT JOIN pull_requests pr ON prr.pull_request_id = pr.pull_request_id
 	WHERE ($1::text IS NULL OR u.team_name = $1::text)
@@ -47,6 +48,7 @@ func (r *StatsRepository) GetUserAssignmentStats(ctx context.Context, teamName *
 			&s.AssignedTotal,
 			&s.AssignedOpen,
 			&s.AssignedMerged,
+			&s.Declined,
 		); err != nil {
 			return nil, err
 		}
@@ -83,3 +85,39 @@ func (r *StatsRepository) GetPRAssignmentStats(ctx context.Context) ([]stats.PRA
 
 	return res, rows.Err()
 }
+
+func (r *StatsRepository) GetReviewDeclines(ctx context.Context, teamName *string) ([]stats.ReviewDecline, error) {
+	const q = `
+	SELECT d.pull_request_id, d.user_id, COALESCE(d.replaced_by, ''), d.reason, d.declined_at
+	FROM review_declines d JOIN users u ON d.user_id = u.user_id
+	WHERE ($1::text IS NULL OR u.team_name = $1::text)
+	ORDER BY d.declined_at DESC, d.id DESC;`
+
+	var arg interface{}
+	if teamName != nil {
+		arg = *teamName
+	}
+
+	rows, err := query(ctx, r.db, q, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []stats.ReviewDecline
+	for rows.Next() {
+		var s stats.ReviewDecline
+		if err := rows.Scan(
+			&s.PullRequestID,
+			&s.UserID,
+			&s.ReplacedBy,
+			&s.Reason,
+			&s.DeclinedAt,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+
+	return res, rows.Err()
+}
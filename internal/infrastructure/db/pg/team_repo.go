@@ -26,34 +26,27 @@ func (r *TeamRepository) Exists(ctx context.Context, name string) (bool, error)
 	return exists, err
 }
 
-func (r *TeamRepository) Create(ctx context.Context, name string) error {
-	_, err := exec(ctx, r.db,
-		`INSERT INTO teams (team_name) VALUES ($1)`,
-		name,
-	)
-	return err
+func (r *TeamRepository) Create(ctx context.Context, name string, settings team.Settings) error {
+	if _, err := exec(ctx, r.db,
+		`INSERT INTO teams (team_name, reviewer_strategy, min_reviewers, max_reviewers, required_approvals, reassign_on_deactivate, mentor_pairing, rotation_window)
+		 VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8)`,
+		name, settings.ReviewerStrategy, settings.MinReviewers, settings.MaxReviewers, settings.RequiredApprovals,
+		settings.ReassignOnDeactivate, settings.MentorPairing, settings.RotationWindow,
+	); err != nil {
+		return err
+	}
+	return r.setPartners(ctx, name, settings.PartnerTeams)
 }
 
 func (r *TeamRepository) GetWithMembers(ctx context.Context, name string) (team.Team, error) {
-	var t team.Team
-	err := queryRow(ctx, r.db,
-		`SELECT team_name FROM teams WHERE team_name = $1`,
-		name,
-	).Scan(&t.Name)
-
-	if errors.Is(err, sql.ErrNoRows) {
-		return team.Team{}, &domain.DomainError{
-			Code:       domain.ErrorCodeNotFound,
-			Message:    "team not found",
-			HTTPStatus: 404,
-		}
-	}
+	settings, err := r.GetSettings(ctx, name)
 	if err != nil {
 		return team.Team{}, err
 	}
+	t := team.Team{Name: name, Settings: settings}
 
 	rows, err := query(ctx, r.db,
-		`SELECT user_id, username, is_active
+		`SELECT user_id, username, is_active, review_weight, COALESCE(max_open_reviews, 0), expertise, COALESCE(seniority, '')
 		   FROM users
 		  WHERE team_name = $1
 		  ORDER BY user_id`,
@@ -66,7 +59,7 @@ func (r *TeamRepository) GetWithMembers(ctx context.Context, name string) (team.
 
 	for rows.Next() {
 		var m team.Member
-		if err := rows.Scan(&m.ID, &m.Username, &m.IsActive); err != nil {
+		if err := rows.Scan(&m.ID, &m.Username, &m.IsActive, &m.ReviewWeight, &m.MaxOpenReviews, textArray(&m.Expertise), &m.Seniority); err != nil {
 			return team.Team{}, err
 		}
 		t.Members = append(t.Members, m)
@@ -76,3 +69,204 @@ func (r *TeamRepository) GetWithMembers(ctx context.Context, name string) (team.
 	}
 	return t, nil
 }
+
+func (r *TeamRepository) GetSettings(ctx context.Context, name string) (team.Settings, error) {
+	var s team.Settings
+	var strategy sql.NullString
+	err := queryRow(ctx, r.db,
+		`SELECT reviewer_strategy, min_reviewers, max_reviewers, required_approvals, reassign_on_deactivate, mentor_pairing, rotation_window
+		   FROM teams
+		  WHERE team_name = $1`,
+		name,
+	).Scan(&strategy, &s.MinReviewers, &s.MaxReviewers, &s.RequiredApprovals, &s.ReassignOnDeactivate, &s.MentorPairing, &s.RotationWindow)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return team.Settings{}, &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "team not found",
+			HTTPStatus: 404,
+		}
+	}
+	if err != nil {
+		return team.Settings{}, err
+	}
+
+	s.ReviewerStrategy = strategy.String
+
+	rows, err := query(ctx, r.db,
+		`SELECT partner_team
+		   FROM team_partners
+		  WHERE team_name = $1
+		  ORDER BY position`,
+		name,
+	)
+	if err != nil {
+		return team.Settings{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var partner string
+		if err := rows.Scan(&partner); err != nil {
+			return team.Settings{}, err
+		}
+		s.PartnerTeams = append(s.PartnerTeams, partner)
+	}
+	return s, rows.Err()
+}
+
+func (r *TeamRepository) UpdateSettings(ctx context.Context, name string, settings team.Settings) error {
+	res, err := exec(ctx, r.db,
+		`UPDATE teams
+		    SET reviewer_strategy = NULLIF($2, ''),
+		        min_reviewers = $3,
+		        max_reviewers = $4,
+		        required_approvals = $5,
+		        reassign_on_deactivate = $6,
+		        mentor_pairing = $7,
+		        rotation_window = $8
+		  WHERE team_name = $1`,
+		name, settings.ReviewerStrategy, settings.MinReviewers, settings.MaxReviewers, settings.RequiredApprovals,
+		settings.ReassignOnDeactivate, settings.MentorPairing, settings.RotationWindow,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "team not found",
+			HTTPStatus: 404,
+		}
+	}
+	return r.setPartners(ctx, name, settings.PartnerTeams)
+}
+
+func (r *TeamRepository) setPartners(ctx context.Context, name string, partners []string) error {
+	if _, err := exec(ctx, r.db,
+		`DELETE FROM team_partners WHERE team_name = $1`,
+		name,
+	); err != nil {
+		return err
+	}
+	for i, p := range partners {
+		if _, err := exec(ctx, r.db,
+			`INSERT INTO team_partners (team_name, partner_team, position)
+			 VALUES ($1, $2, $3)`,
+			name, p, i,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TeamRepository) GetOwnershipRules(ctx context.Context, name string) ([]team.OwnershipRule, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT pattern, owners
+		   FROM team_ownership_rules
+		  WHERE team_name = $1
+		  ORDER BY position`,
+		name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []team.OwnershipRule
+	for rows.Next() {
+		var rule team.OwnershipRule
+		if err := rows.Scan(&rule.Pattern, textArray(&rule.Owners)); err != nil {
+			return nil, err
+		}
+		res = append(res, rule)
+	}
+	return res, rows.Err()
+}
+
+func (r *TeamRepository) SetOwnershipRules(ctx context.Context, name string, rules []team.OwnershipRule) error {
+	if _, err := exec(ctx, r.db,
+		`DELETE FROM team_ownership_rules WHERE team_name = $1`,
+		name,
+	); err != nil {
+		return err
+	}
+	for i, rule := range rules {
+		if _, err := exec(ctx, r.db,
+			`INSERT INTO team_ownership_rules (team_name, position, pattern, owners)
+			 VALUES ($1, $2, $3, $4)`,
+			name, i, rule.Pattern, stringList(rule.Owners),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TeamRepository) AddPairRule(ctx context.Context, rule team.PairRule) (team.PairRule, error) {
+	err := queryRow(ctx, r.db,
+		`INSERT INTO team_pair_rules (team_name, reviewer_id, author_id, kind, reason)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id`,
+		rule.TeamName, rule.ReviewerID, rule.AuthorID, string(rule.Kind), rule.Reason,
+	).Scan(&rule.ID)
+	if err != nil {
+		return team.PairRule{}, err
+	}
+	return rule, nil
+}
+
+func (r *TeamRepository) ListPairRules(ctx context.Context, name string) ([]team.PairRule, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT id, team_name, reviewer_id, author_id, kind, reason
+		   FROM team_pair_rules
+		  WHERE team_name = $1
+		  ORDER BY id`,
+		name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []team.PairRule
+	for rows.Next() {
+		var rule team.PairRule
+		if err := rows.Scan(&rule.ID, &rule.TeamName, &rule.ReviewerID, &rule.AuthorID, &rule.Kind, &rule.Reason); err != nil {
+			return nil, err
+		}
+		res = append(res, rule)
+	}
+	return res, rows.Err()
+}
+
+func (r *TeamRepository) DeletePairRule(ctx context.Context, name string, id int64) error {
+	res, err := exec(ctx, r.db,
+		`DELETE FROM team_pair_rules
+		  WHERE team_name = $1
+		    AND id = $2`,
+		name, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "pair rule not found",
+			HTTPStatus: 404,
+		}
+	}
+	return nil
+}
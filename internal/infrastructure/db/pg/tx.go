@@ -8,12 +8,23 @@ import (
 	"github.com/avito-tech/go-transaction-manager/trm/v2"
 	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
 	trmmanager "github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"prservice/internal/domain"
 )
 
 var ctxGetter = trmsql.DefaultCtxGetter
 
+var typeMap = pgtype.NewMap()
+
+func textArray(dst *[]string) sql.Scanner {
+	return typeMap.SQLScanner(dst)
+}
+
+func stringList(v []string) []string {
+	return append([]string{}, v...)
+}
+
 type TxManager struct {
 	tm trm.Manager
 }
@@ -20,13 +20,18 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 func (r *UserRepository) UpsertInTeam(ctx context.Context, teamName string, members []user.User) error {
 	for _, u := range members {
 		if _, err := exec(ctx, r.db,
-			`INSERT INTO users (user_id, username, team_name, is_active)
-			 VALUES ($1, $2, $3, $4)
+			`INSERT INTO users (user_id, username, team_name, is_active, review_weight, max_open_reviews, expertise, seniority)
+			 VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, NULLIF($8, ''))
 			 ON CONFLICT (user_id) DO UPDATE
 			   SET username = EXCLUDED.username,
 			       team_name = EXCLUDED.team_name,
-			       is_active = EXCLUDED.is_active`,
-			u.ID, u.Username, teamName, u.IsActive,
+			       is_active = EXCLUDED.is_active,
+			       review_weight = EXCLUDED.review_weight,
+			       max_open_reviews = EXCLUDED.max_open_reviews,
+			       expertise = EXCLUDED.expertise,
+			       seniority = EXCLUDED.seniority`,
+			u.ID, u.Username, teamName, u.IsActive, u.ReviewWeight, u.MaxOpenReviews, stringList(u.Expertise),
+			string(u.Seniority),
 		); err != nil {
 			return err
 		}
@@ -34,15 +39,22 @@ func (r *UserRepository) UpsertInTeam(ctx context.Context, teamName string, memb
 	return nil
 }
 
-func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive bool) (user.User, error) {
+const userColumns = `user_id, username, team_name, is_active, review_weight, COALESCE(max_open_reviews, 0), expertise, COALESCE(seniority, '')`
+
+func scanUser(row interface{ Scan(dest ...any) error }) (user.User, error) {
 	var u user.User
-	err := queryRow(ctx, r.db,
+	err := row.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.ReviewWeight, &u.MaxOpenReviews, textArray(&u.Expertise), &u.Seniority)
+	return u, err
+}
+
+func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive bool) (user.User, error) {
+	u, err := scanUser(queryRow(ctx, r.db,
 		`UPDATE users
 		   	SET is_active = $2
 		 	WHERE user_id = $1
-		 	RETURNING user_id, username, team_name, is_active`,
+		 	RETURNING `+userColumns,
 		userID, isActive,
-	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
+	))
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return user.User{}, &domain.DomainError{
@@ -59,13 +71,12 @@ func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, userID string) (user.User, error) {
-	var u user.User
-	err := queryRow(ctx, r.db,
-		`SELECT user_id, username, team_name, is_active
+	u, err := scanUser(queryRow(ctx, r.db,
+		`SELECT `+userColumns+`
 		   	FROM users
 		  	WHERE user_id = $1`,
 		userID,
-	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
+	))
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return user.User{}, &domain.DomainError{
@@ -81,13 +92,49 @@ func (r *UserRepository) GetByID(ctx context.Context, userID string) (user.User,
 	return u, nil
 }
 
+// FindByHandle returns the user whose user_id equals handle or, when there
+// is none, every user whose username matches it case-insensitively.
+func (r *UserRepository) FindByHandle(ctx context.Context, handle string) ([]user.User, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT `+userColumns+`
+		   FROM users
+		  WHERE user_id = $1
+		     OR (LOWER(username) = LOWER($1)
+		         AND NOT EXISTS (SELECT 1 FROM users WHERE user_id = $1))
+		  ORDER BY user_id`,
+		handle,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []user.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+
+	return res, rows.Err()
+}
+
 func (r *UserRepository) GetActiveTeamMembersExcept(ctx context.Context, teamName, excludeUserID string) ([]user.User, error) {
 	rows, err := query(ctx, r.db,
-		`SELECT user_id, username, team_name, is_active
+		`SELECT `+userColumns+`
 		   	FROM users
 		  	WHERE team_name = $1
 		    AND is_active = TRUE
-		    AND user_id <> $2`,
+		    AND user_id <> $2
+		    AND NOT EXISTS (
+		        SELECT 1
+		          FROM user_unavailability ua
+		         WHERE ua.user_id = users.user_id
+		           AND ua.starts_at <= NOW()
+		           AND ua.ends_at > NOW()
+		    )`,
 		teamName, excludeUserID,
 	)
 	if err != nil {
@@ -97,11 +144,74 @@ func (r *UserRepository) GetActiveTeamMembersExcept(ctx context.Context, teamNam
 
 	var res []user.User
 	for rows.Next() {
-		var u user.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+	return res, rows.Err()
+}
+
+func (r *UserRepository) AddUnavailability(ctx context.Context, u user.Unavailability) (user.Unavailability, error) {
+	err := queryRow(ctx, r.db,
+		`INSERT INTO user_unavailability (user_id, starts_at, ends_at, reason)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id`,
+		u.UserID, u.StartsAt, u.EndsAt, u.Reason,
+	).Scan(&u.ID)
+	if err != nil {
+		return user.Unavailability{}, err
+	}
+	return u, nil
+}
+
+func (r *UserRepository) ListUnavailability(ctx context.Context, userID string) ([]user.Unavailability, error) {
+	rows, err := query(ctx, r.db,
+		`SELECT id, user_id, starts_at, ends_at, reason
+		   FROM user_unavailability
+		  WHERE user_id = $1
+		    AND ends_at > NOW()
+		  ORDER BY starts_at, id`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []user.Unavailability
+	for rows.Next() {
+		var u user.Unavailability
+		if err := rows.Scan(&u.ID, &u.UserID, &u.StartsAt, &u.EndsAt, &u.Reason); err != nil {
 			return nil, err
 		}
 		res = append(res, u)
 	}
 	return res, rows.Err()
 }
+
+func (r *UserRepository) DeleteUnavailability(ctx context.Context, userID string, id int64) error {
+	res, err := exec(ctx, r.db,
+		`DELETE FROM user_unavailability
+		  WHERE id = $1
+		    AND user_id = $2`,
+		id, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "unavailability not found",
+			HTTPStatus: 404,
+		}
+	}
+	return nil
+}
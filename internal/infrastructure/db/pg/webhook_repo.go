@@ -0,0 +1,237 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"prservice/internal/domain"
+	"prservice/internal/domain/webhook"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s webhook.Subscription) (webhook.Subscription, error) {
+	err := queryRow(ctx, r.db,
+		`INSERT INTO webhook_subscriptions (url, event_types, secret)
+		 VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		s.URL, stringList(s.EventTypes), s.Secret,
+	).Scan(&s.ID, &s.CreatedAt)
+	return s, err
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]webhook.Subscription, error) {
+	return r.listSubscriptions(ctx,
+		`SELECT id, url, event_types, secret, created_at
+		   FROM webhook_subscriptions
+		  ORDER BY id`,
+	)
+}
+
+func (r *WebhookRepository) SubscriptionsFor(ctx context.Context, eventType string) ([]webhook.Subscription, error) {
+	return r.listSubscriptions(ctx,
+		`SELECT id, url, event_types, secret, created_at
+		   FROM webhook_subscriptions
+		  WHERE cardinality(event_types) = 0 OR $1 = ANY(event_types)
+		  ORDER BY id`,
+		eventType,
+	)
+}
+
+func (r *WebhookRepository) listSubscriptions(ctx context.Context, q string, args ...any) ([]webhook.Subscription, error) {
+	rows, err := query(ctx, r.db, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []webhook.Subscription
+	for rows.Next() {
+		var s webhook.Subscription
+		if err := rows.Scan(&s.ID, &s.URL, textArray(&s.EventTypes), &s.Secret, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	res, err := exec(ctx, r.db, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &domain.DomainError{
+			Code:       domain.ErrorCodeNotFound,
+			Message:    "webhook subscription not found",
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueDelivery(ctx context.Context, subscriptionID int64, env domain.Envelope) error {
+	_, err := exec(ctx, r.db,
+		`INSERT INTO webhook_deliveries
+		        (subscription_id, event_id, event_type, event_version, occurred_at, actor, payload)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (subscription_id, event_id) DO UPDATE
+		    SET status = 'pending',
+		        attempts = 0,
+		        response_status = NULL,
+		        last_error = NULL,
+		        next_attempt_at = NOW(),
+		        delivered_at = NULL
+		  WHERE $8`,
+		subscriptionID, env.ID, env.Type, env.Version, env.OccurredAt, env.Actor, string(env.Payload), env.Replayed,
+	)
+	return err
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]webhook.DueDelivery, error) {
+	rows, err := query(ctx, r.db,
+		`WITH due AS (
+		        SELECT id
+		          FROM webhook_deliveries
+		         WHERE status = 'pending' AND next_attempt_at <= NOW()
+		         ORDER BY next_attempt_at, id
+		         LIMIT $1
+		           FOR UPDATE SKIP LOCKED
+		 )
+		 UPDATE webhook_deliveries d
+		    SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		   FROM due, webhook_subscriptions s
+		  WHERE d.id = due.id AND s.id = d.subscription_id
+		 RETURNING d.id, d.subscription_id,
+		        d.event_id, d.event_type, d.event_version, d.occurred_at, d.actor, d.payload,
+		        d.status, d.attempts,
+		        COALESCE(d.response_status, 0), COALESCE(d.last_error, ''),
+		        d.next_attempt_at, d.created_at, d.delivered_at,
+		        s.url, s.event_types, s.secret, s.created_at`,
+		limit, lease.Milliseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []webhook.DueDelivery
+	for rows.Next() {
+		var dd webhook.DueDelivery
+		d := &dd.Delivery
+		var payload []byte
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID,
+			&d.Event.ID, &d.Event.Type, &d.Event.Version, &d.Event.OccurredAt, &d.Event.Actor, &payload,
+			&d.Status, &d.Attempts,
+			&d.ResponseStatus, &d.LastError,
+			&d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt,
+			&dd.Subscription.URL, textArray(&dd.Subscription.EventTypes), &dd.Subscription.Secret, &dd.Subscription.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		d.Event.Payload = payload
+		dd.Subscription.ID = d.SubscriptionID
+		res = append(res, dd)
+	}
+	return res, rows.Err()
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	_, err := exec(ctx, r.db,
+		`UPDATE webhook_deliveries
+		    SET status = 'delivered',
+		        attempts = attempts + 1,
+		        response_status = $2,
+		        last_error = NULL,
+		        delivered_at = NOW()
+		  WHERE id = $1`,
+		id, responseStatus,
+	)
+	return err
+}
+
+func (r *WebhookRepository) ScheduleRetry(ctx context.Context, id int64, responseStatus int, reason string, delay time.Duration) error {
+	_, err := exec(ctx, r.db,
+		`UPDATE webhook_deliveries
+		    SET attempts = attempts + 1,
+		        response_status = NULLIF($2, 0),
+		        last_error = $3,
+		        next_attempt_at = NOW() + $4 * INTERVAL '1 millisecond'
+		  WHERE id = $1`,
+		id, responseStatus, reason, delay.Milliseconds(),
+	)
+	return err
+}
+
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, responseStatus int, reason string) error {
+	_, err := exec(ctx, r.db,
+		`UPDATE webhook_deliveries
+		    SET status = 'failed',
+		        attempts = attempts + 1,
+		        response_status = NULLIF($2, 0),
+		        last_error = $3
+		  WHERE id = $1`,
+		id, responseStatus, reason,
+	)
+	return err
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, f webhook.DeliveryFilter) ([]webhook.Delivery, error) {
+	var subID, status any
+	if f.SubscriptionID != nil {
+		subID = *f.SubscriptionID
+	}
+	if f.Status != nil {
+		status = string(*f.Status)
+	}
+
+	rows, err := query(ctx, r.db,
+		`SELECT id, subscription_id,
+		        event_id, event_type, event_version, occurred_at, actor, payload,
+		        status, attempts,
+		        COALESCE(response_status, 0), COALESCE(last_error, ''),
+		        next_attempt_at, created_at, delivered_at
+		   FROM webhook_deliveries
+		  WHERE ($1::bigint IS NULL OR subscription_id = $1::bigint)
+		    AND ($2::text IS NULL OR status = $2::text)
+		  ORDER BY id DESC
+		  LIMIT $3`,
+		subID, status, f.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []webhook.Delivery
+	for rows.Next() {
+		var d webhook.Delivery
+		var payload []byte
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID,
+			&d.Event.ID, &d.Event.Type, &d.Event.Version, &d.Event.OccurredAt, &d.Event.Actor, &payload,
+			&d.Status, &d.Attempts,
+			&d.ResponseStatus, &d.LastError,
+			&d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		d.Event.Payload = payload
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
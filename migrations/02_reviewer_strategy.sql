@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewer_strategy TEXT;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS review_weight INT NOT NULL DEFAULT 1 CHECK (review_weight > 0);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS review_weight;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewer_strategy;
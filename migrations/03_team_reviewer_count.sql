@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS min_reviewers INT NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS max_reviewers INT NOT NULL DEFAULT 2;
+ALTER TABLE teams ADD CONSTRAINT teams_reviewer_count_check
+    CHECK (min_reviewers >= 0 AND max_reviewers >= 1 AND min_reviewers <= max_reviewers);
+
+-- +goose Down
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_reviewer_count_check;
+ALTER TABLE teams DROP COLUMN IF EXISTS max_reviewers;
+ALTER TABLE teams DROP COLUMN IF EXISTS min_reviewers;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS state TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'DISMISSED')),
+    ADD COLUMN IF NOT EXISTS comment TEXT,
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS assigned_at,
+    DROP COLUMN IF EXISTS comment,
+    DROP COLUMN IF EXISTS state;
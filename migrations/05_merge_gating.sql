@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS merge_forced BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS merge_forced;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reassign_on_deactivate BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE teams DROP COLUMN IF EXISTS reassign_on_deactivate;
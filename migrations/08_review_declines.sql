@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS review_declines (
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id         TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    replaced_by     TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    reason          TEXT NOT NULL,
+    declined_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_declines_user ON review_declines(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS review_declines;
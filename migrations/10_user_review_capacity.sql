@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_open_reviews INT CHECK (max_open_reviews > 0);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS expertise TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS changed_files TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS team_ownership_rules (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position  INT NOT NULL,
+    pattern   TEXT NOT NULL,
+    owners    TEXT[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (team_name, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS team_ownership_rules;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS labels;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS changed_files;
+ALTER TABLE users DROP COLUMN IF EXISTS expertise;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS team_partners (
+    team_name    TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    partner_team TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position     INT NOT NULL,
+    PRIMARY KEY (team_name, partner_team),
+    CONSTRAINT team_partners_self_check CHECK (team_name <> partner_team)
+);
+
+ALTER TABLE pull_request_reviewers ADD COLUMN IF NOT EXISTS source_team TEXT;
+
+UPDATE pull_request_reviewers prr
+   SET source_team = u.team_name
+  FROM users u
+ WHERE u.user_id = prr.user_id
+   AND prr.source_team IS NULL;
+
+-- +goose Down
+ALTER TABLE pull_request_reviewers DROP COLUMN IF EXISTS source_team;
+DROP TABLE IF EXISTS team_partners;
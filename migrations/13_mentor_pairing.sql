@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS seniority TEXT CHECK (seniority IN ('junior', 'middle', 'senior'));
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS mentor_pairing BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE teams DROP COLUMN IF EXISTS mentor_pairing;
+ALTER TABLE users DROP COLUMN IF EXISTS seniority;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS team_pair_rules (
+    id          BIGSERIAL PRIMARY KEY,
+    team_name   TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    author_id   TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    kind        TEXT NOT NULL CHECK (kind IN ('exclude', 'prefer')),
+    reason      TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT team_pair_rules_pair_key UNIQUE (team_name, reviewer_id, author_id),
+    CONSTRAINT team_pair_rules_self_check CHECK (reviewer_id <> author_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS team_pair_rules;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS rotation_window INTEGER NOT NULL DEFAULT 0 CHECK (rotation_window >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_created
+    ON pull_requests (author_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_pull_requests_author_created;
+ALTER TABLE teams DROP COLUMN IF EXISTS rotation_window;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS pull_request_assignments (
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    operation       TEXT NOT NULL,
+    decisions       JSONB NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_assignments_pr
+    ON pull_request_assignments (pull_request_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS pull_request_assignments;
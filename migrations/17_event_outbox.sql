@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id            BIGSERIAL PRIMARY KEY,
+    event_type    TEXT NOT NULL,
+    payload       JSONB NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMPTZ,
+    attempts      INTEGER NOT NULL DEFAULT 0,
+    last_error    TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending
+    ON event_outbox (id)
+    WHERE dispatched_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS event_outbox;
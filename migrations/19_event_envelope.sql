@@ -0,0 +1,45 @@
+-- +goose Up
+ALTER TABLE event_outbox
+    ADD COLUMN IF NOT EXISTS event_id      TEXT,
+    ADD COLUMN IF NOT EXISTS event_version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS occurred_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS actor         TEXT NOT NULL DEFAULT 'system';
+
+UPDATE event_outbox
+   SET event_id = gen_random_uuid()::text,
+       occurred_at = created_at
+ WHERE event_id IS NULL;
+
+ALTER TABLE event_outbox
+    ALTER COLUMN event_id SET NOT NULL,
+    ADD CONSTRAINT event_outbox_event_id_key UNIQUE (event_id);
+
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS event_id      TEXT,
+    ADD COLUMN IF NOT EXISTS event_version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS occurred_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS actor         TEXT NOT NULL DEFAULT 'system';
+
+UPDATE webhook_deliveries
+   SET event_id = gen_random_uuid()::text,
+       occurred_at = created_at
+ WHERE event_id IS NULL;
+
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN event_id SET NOT NULL,
+    ADD CONSTRAINT webhook_deliveries_subscription_event_key UNIQUE (subscription_id, event_id);
+
+-- +goose Down
+ALTER TABLE webhook_deliveries
+    DROP CONSTRAINT IF EXISTS webhook_deliveries_subscription_event_key,
+    DROP COLUMN IF EXISTS actor,
+    DROP COLUMN IF EXISTS occurred_at,
+    DROP COLUMN IF EXISTS event_version,
+    DROP COLUMN IF EXISTS event_id;
+
+ALTER TABLE event_outbox
+    DROP CONSTRAINT IF EXISTS event_outbox_event_id_key,
+    DROP COLUMN IF EXISTS actor,
+    DROP COLUMN IF EXISTS occurred_at,
+    DROP COLUMN IF EXISTS event_version,
+    DROP COLUMN IF EXISTS event_id;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS events (
+    seq             BIGSERIAL PRIMARY KEY,
+    event_id        TEXT NOT NULL UNIQUE,
+    event_type      TEXT NOT NULL,
+    event_version   INTEGER NOT NULL,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    actor           TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    pull_request_id TEXT GENERATED ALWAYS AS (payload->>'pull_request_id') STORED,
+    recorded_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_type_seq ON events (event_type, seq);
+CREATE INDEX IF NOT EXISTS idx_events_pull_request_seq ON events (pull_request_id, seq)
+    WHERE pull_request_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_events_occurred_at ON events (occurred_at);
+
+INSERT INTO events (event_id, event_type, event_version, occurred_at, actor, payload)
+SELECT event_id, event_type, event_version, occurred_at, actor, payload
+  FROM event_outbox
+ WHERE dispatched_at IS NOT NULL
+ ORDER BY id
+ON CONFLICT (event_id) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE event_outbox
+    ADD COLUMN IF NOT EXISTS aggregate       TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS dead_at         TIMESTAMPTZ;
+
+UPDATE event_outbox
+   SET aggregate = CASE split_part(event_type, '.', 1)
+           WHEN 'pr'   THEN 'pr:' || COALESCE(payload->>'pull_request_id', '')
+           WHEN 'team' THEN 'team:' || COALESCE(payload->>'team_name', '')
+           WHEN 'user' THEN 'user:' || COALESCE(payload->>'user_id', '')
+           ELSE split_part(event_type, '.', 1)
+       END
+ WHERE dispatched_at IS NULL;
+
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending
+    ON event_outbox (aggregate, id)
+    WHERE dispatched_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending
+    ON event_outbox (id)
+    WHERE dispatched_at IS NULL;
+
+ALTER TABLE event_outbox
+    DROP COLUMN IF EXISTS dead_at,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS aggregate;
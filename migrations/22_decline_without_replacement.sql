@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE review_declines ALTER COLUMN replaced_by DROP NOT NULL;
+
+-- +goose Down
+DELETE FROM review_declines WHERE replaced_by IS NULL;
+ALTER TABLE review_declines ALTER COLUMN replaced_by SET NOT NULL;
@@ -0,0 +1,8 @@
+-- +goose Up
+UPDATE teams SET required_approvals = max_reviewers WHERE required_approvals > max_reviewers;
+
+ALTER TABLE teams ADD CONSTRAINT teams_required_approvals_check
+    CHECK (required_approvals <= max_reviewers);
+
+-- +goose Down
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_required_approvals_check;
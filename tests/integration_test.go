@@ -1,15 +1,20 @@
 package integration
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -20,20 +25,37 @@ import (
 	"prservice/internal/app/dto"
 	httpapi "prservice/internal/app/http"
 	"prservice/internal/app/http/handler"
+	"prservice/internal/app/http/middleware"
+	"prservice/internal/domain"
+	"prservice/internal/domain/eventlog"
 	"prservice/internal/domain/pr"
 	"prservice/internal/domain/stats"
 	"prservice/internal/domain/team"
 	userdomain "prservice/internal/domain/user"
+	"prservice/internal/domain/webhook"
 	"prservice/internal/infrastructure/async"
 	"prservice/internal/infrastructure/db/pg"
 	"prservice/internal/infrastructure/logging"
+	webhookhttp "prservice/internal/infrastructure/webhook"
 )
 
+const testAdminToken = "test-admin-token"
+
+var adminHeaders = map[string]string{middleware.AdminTokenHeader: testAdminToken}
+
 type testRandSource struct{}
 
 func (testRandSource) Shuffle(n int, swap func(i, j int)) {
 }
 
+func (testRandSource) Intn(n int) int {
+	return 0
+}
+
+func (testRandSource) WithSeed(seed int64) domain.RandomSource {
+	return testRandSource{}
+}
+
 var migrateOnce sync.Once
 
 func ensureMigrations(t *testing.T, db *sql.DB) {
@@ -67,7 +89,7 @@ func resetDB(t *testing.T, db *sql.DB) {
 	defer cancel()
 
 	if _, err := db.ExecContext(ctx, `
-		TRUNCATE TABLE pull_request_reviewers, pull_requests, users, teams
+		TRUNCATE TABLE pull_request_reviewers, pull_requests, users, teams, event_outbox, webhook_subscriptions, events
 		RESTART IDENTITY CASCADE;
 	`); err != nil {
 		t.Fatalf("truncate tables: %v", err)
@@ -131,23 +153,50 @@ func setupTestServer(t *testing.T) (*httptest.Server, func()) {
 	eventBus := async.NewAsyncEventBus(ctx, 4, log)
 	uow := pg.NewTxManager(db)
 
+	webhookSvc := webhook.NewService(uow, pg.NewWebhookRepository(db), webhookhttp.NewHTTPSender(time.Second), webhook.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   20 * time.Millisecond,
+		MaxDelay:    100 * time.Millisecond,
+	}, func(string) error { return nil }) // receivers are local httptest servers
+	dispatcher := async.NewWebhookDispatcher(webhookSvc, 20*time.Millisecond, 20, log)
+	dispatcher.Start(ctx)
+
+	subscribers := async.FanoutBus{eventBus, webhookSvc}
+	eventLogSvc := eventlog.NewService(uow, pg.NewEventLogRepository(db), subscribers)
+
+	outbox := pg.NewOutbox(db)
+	relay := async.NewOutboxRelay(uow, outbox, async.FanoutBus{eventLogSvc, webhookSvc}, eventBus, async.DefaultOutboxRetryPolicy(), 50*time.Millisecond, 100, log)
+	relay.Start(ctx)
+
 	teamRepo := pg.NewTeamRepository(db)
 	userRepo := pg.NewUserRepository(db)
 	prRepo := pg.NewPRRepository(db)
 	statsRepo := pg.NewStatsRepository(db)
 
-	teamSvc := team.NewService(uow, teamRepo, userRepo, eventBus)
-	userSvc := userdomain.NewService(uow, userRepo, eventBus)
-	prSvc := pr.NewService(uow, prRepo, userRepo, eventBus, testRandSource{})
+	selectors, err := pr.NewSelectors(pr.StrategyRandom, map[pr.Strategy]pr.ReviewerSelector{
+		pr.StrategyRandom:   pr.NewRandomSelector(testRandSource{}),
+		pr.StrategyRotation: pr.NewRotationSelector(prRepo, teamRepo, testRandSource{}, 0),
+	})
+	if err != nil {
+		t.Fatalf("selectors: %v", err)
+	}
+
+	prSvc := pr.NewService(uow, prRepo, userRepo, teamRepo, outbox, selectors)
+	teamSvc := team.NewService(uow, teamRepo, userRepo, prSvc, outbox)
+	userSvc := userdomain.NewService(uow, userRepo, prSvc, teamSvc, outbox)
 	statsSvc := stats.NewService(statsRepo)
 
-	h := handler.New(teamSvc, userSvc, prSvc, statsSvc, log)
-	router := httpapi.NewRouter(h, log)
+	h := handler.New(teamSvc, userSvc, prSvc, statsSvc, webhookSvc, eventLogSvc, eventBus, log)
+	h.StreamHeartbeat = 50 * time.Millisecond
+	router := httpapi.NewRouter(h, log, testAdminToken)
 
 	ts := httptest.NewServer(router)
 
 	cleanup := func() {
+		eventBus.CloseSubscriptions()
 		ts.Close()
+		relay.Close()
+		dispatcher.Close()
 		eventBus.Close()
 		cancel()
 		_ = log.Sync()
@@ -159,6 +208,11 @@ func setupTestServer(t *testing.T) (*httptest.Server, func()) {
 
 func doPost(t *testing.T, client *http.Client, url string, body any, wantStatus int, out any) {
 	t.Helper()
+	doPostWithHeaders(t, client, url, nil, body, wantStatus, out)
+}
+
+func doPostWithHeaders(t *testing.T, client *http.Client, url string, headers map[string]string, body any, wantStatus int, out any) {
+	t.Helper()
 
 	var buf bytes.Buffer
 	if body != nil {
@@ -172,6 +226,9 @@ func doPost(t *testing.T, client *http.Client, url string, body any, wantStatus
 		t.Fatalf("new request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -194,8 +251,21 @@ func doPost(t *testing.T, client *http.Client, url string, body any, wantStatus
 
 func doGet(t *testing.T, client *http.Client, url string, wantStatus int, out any) {
 	t.Helper()
+	doGetWithHeaders(t, client, url, nil, wantStatus, out)
+}
+
+func doGetWithHeaders(t *testing.T, client *http.Client, url string, headers map[string]string, wantStatus int, out any) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
 		t.Fatalf("do GET %s: %v", url, err)
 	}
@@ -476,3 +546,1482 @@ func TestIntegration_UserGetReview(t *testing.T) {
 		t.Fatalf("expected PR %s in user reviews", prResp.PR.PullRequestID)
 	}
 }
+
+func TestIntegration_TeamReviewerCount(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	teamBody := dto.Team{
+		TeamName: "small",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	var updResp struct {
+		Team dto.Team `json:"team"`
+	}
+	doPost(t, client, ts.URL+"/team/update", map[string]any{
+		"team_name":     "small",
+		"max_reviewers": 1,
+	}, http.StatusOK, &updResp)
+
+	if updResp.Team.MaxReviewers == nil || *updResp.Team.MaxReviewers != 1 {
+		t.Fatalf("expected max_reviewers=1, got %v", updResp.Team.MaxReviewers)
+	}
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-5001",
+		"pull_request_name": "Small change",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	if len(prResp.PR.AssignedReviewers) != 1 {
+		t.Fatalf("expected exactly 1 reviewer, got %v", prResp.PR.AssignedReviewers)
+	}
+
+	doPost(t, client, ts.URL+"/team/update", map[string]any{
+		"team_name":     "small",
+		"min_reviewers": 2,
+	}, http.StatusBadRequest, nil)
+}
+
+func TestIntegration_ReviewDecisions(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	teamBody := dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-6001",
+		"pull_request_name": "Review me",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	if len(prResp.PR.Reviews) == 0 {
+		t.Fatalf("expected reviews for assigned reviewers")
+	}
+	for _, r := range prResp.PR.Reviews {
+		if r.State != "PENDING" {
+			t.Fatalf("expected PENDING after create, got %s", r.State)
+		}
+	}
+
+	reviewer := prResp.PR.AssignedReviewers[0]
+
+	var revResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/review", map[string]string{
+		"pull_request_id": "pr-6001",
+		"user_id":         reviewer,
+		"action":          "APPROVE",
+		"comment":         "LGTM",
+	}, http.StatusOK, &revResp)
+
+	found := false
+	for _, r := range revResp.PR.Reviews {
+		if r.UserID == reviewer {
+			found = true
+			if r.State != "APPROVED" || r.Comment != "LGTM" || r.UpdatedAt == nil {
+				t.Fatalf("unexpected review after approve: %+v", r)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("review of %s not found", reviewer)
+	}
+
+	doPost(t, client, ts.URL+"/pullRequest/review", map[string]string{
+		"pull_request_id": "pr-6001",
+		"user_id":         "u1",
+		"action":          "APPROVE",
+	}, http.StatusConflict, nil)
+}
+
+func TestIntegration_DeactivateReassignsReviews(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	teamBody := dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "John", IsActive: true},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-7001",
+		"pull_request_name": "Offboarding",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	if len(prResp.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", prResp.PR.AssignedReviewers)
+	}
+	leaving := prResp.PR.AssignedReviewers[0]
+
+	var setResp struct {
+		User         dto.User            `json:"user"`
+		Reassignment *dto.ReassignReport `json:"reassignment"`
+	}
+	doPost(t, client, ts.URL+"/users/setIsActive?reassign=true", map[string]any{
+		"user_id":   leaving,
+		"is_active": false,
+	}, http.StatusOK, &setResp)
+
+	if setResp.User.IsActive {
+		t.Fatalf("user must be deactivated")
+	}
+	if setResp.Reassignment == nil || len(setResp.Reassignment.Reassigned) != 1 {
+		t.Fatalf("expected one reassigned PR, got %+v", setResp.Reassignment)
+	}
+	got := setResp.Reassignment.Reassigned[0]
+	if got.PullRequestID != "pr-7001" || got.ReplacedBy == leaving || got.ReplacedBy == "u1" {
+		t.Fatalf("unexpected reassignment %+v", got)
+	}
+
+	var reviewResp struct {
+		PullRequests []dto.PullRequestShort `json:"pull_requests"`
+	}
+	doGet(t, client, ts.URL+"/users/getReview?user_id="+leaving, http.StatusOK, &reviewResp)
+	if len(reviewResp.PullRequests) != 0 {
+		t.Fatalf("deactivated user must have no reviews, got %+v", reviewResp.PullRequests)
+	}
+}
+
+func TestIntegration_TeamDeactivateUsers(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	teamBody := dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "John", IsActive: true},
+			{UserID: "u5", Username: "Kate", IsActive: true},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	const prCount = 200
+	for i := 0; i < prCount; i++ {
+		doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+			"pull_request_id":   fmt.Sprintf("pr-8%03d", i),
+			"pull_request_name": "Bulk",
+			"author_id":         "u1",
+		}, http.StatusCreated, nil)
+	}
+
+	var resp struct {
+		Users        []dto.User         `json:"users"`
+		Reassignment dto.ReassignReport `json:"reassignment"`
+	}
+	// the number of round trips is asserted by the unit tests of
+	// redistribute; the timing is only logged for comparison
+	started := time.Now()
+	doPost(t, client, ts.URL+"/team/deactivateUsers", map[string]any{
+		"team_name": "backend",
+		"user_ids":  []string{"u2", "u3"},
+	}, http.StatusOK, &resp)
+	t.Logf("deactivateUsers for %d PRs took %s", prCount, time.Since(started))
+
+	if len(resp.Users) != 2 {
+		t.Fatalf("expected 2 deactivated users, got %+v", resp.Users)
+	}
+	for _, r := range resp.Reassignment.Reassigned {
+		if r.ReplacedBy != "u4" && r.ReplacedBy != "u5" {
+			t.Fatalf("unexpected replacement %+v", r)
+		}
+	}
+
+	for _, id := range []string{"u2", "u3"} {
+		var reviewResp struct {
+			PullRequests []dto.PullRequestShort `json:"pull_requests"`
+		}
+		doGet(t, client, ts.URL+"/users/getReview?user_id="+id, http.StatusOK, &reviewResp)
+		if len(reviewResp.PullRequests) != 0 {
+			t.Fatalf("%s must have no reviews left, got %d", id, len(reviewResp.PullRequests))
+		}
+	}
+
+	doPost(t, client, ts.URL+"/team/deactivateUsers", map[string]any{
+		"team_name": "backend",
+		"user_ids":  []string{"missing"},
+	}, http.StatusNotFound, nil)
+}
+
+func TestIntegration_DeclineReview(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	teamBody := dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "John", IsActive: true},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-9001",
+		"pull_request_name": "Decline me",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	decliner := prResp.PR.AssignedReviewers[0]
+
+	doPost(t, client, ts.URL+"/pullRequest/decline", map[string]string{
+		"pull_request_id": "pr-9001",
+		"user_id":         decliner,
+	}, http.StatusBadRequest, nil)
+
+	var declResp struct {
+		PR         dto.PullRequest `json:"pr"`
+		ReplacedBy string          `json:"replaced_by"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/decline", map[string]string{
+		"pull_request_id": "pr-9001",
+		"user_id":         decliner,
+		"reason":          "no context",
+	}, http.StatusOK, &declResp)
+
+	if declResp.ReplacedBy == "" || declResp.ReplacedBy == decliner {
+		t.Fatalf("unexpected replacement %q", declResp.ReplacedBy)
+	}
+
+	var statsResp dto.StatsResponse
+	doGet(t, client, ts.URL+"/stats/assignments?scope=all", http.StatusOK, &statsResp)
+
+	if len(statsResp.Declines) != 1 || statsResp.Declines[0].Reason != "no context" || statsResp.Declines[0].UserID != decliner {
+		t.Fatalf("decline not surfaced in stats: %+v", statsResp.Declines)
+	}
+	for _, s := range statsResp.PerUser {
+		if s.UserID == decliner && s.Declined != 1 {
+			t.Fatalf("expected declined=1 for %s, got %d", decliner, s.Declined)
+		}
+	}
+}
+
+func TestIntegration_UnavailabilitySkipsReviewers(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	teamBody := dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	now := time.Now().UTC()
+	var addResp struct {
+		Unavailability dto.Unavailability `json:"unavailability"`
+	}
+	doPost(t, client, ts.URL+"/users/addUnavailability", map[string]any{
+		"user_id":   "u2",
+		"starts_at": now.Add(-time.Hour),
+		"ends_at":   now.Add(24 * time.Hour),
+		"reason":    "vacation",
+	}, http.StatusCreated, &addResp)
+
+	doPost(t, client, ts.URL+"/users/addUnavailability", map[string]any{
+		"user_id":   "u3",
+		"starts_at": now,
+		"ends_at":   now.Add(-time.Hour),
+	}, http.StatusBadRequest, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-10001",
+		"pull_request_name": "Vacation",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	if len(prResp.PR.AssignedReviewers) != 1 || prResp.PR.AssignedReviewers[0] != "u3" {
+		t.Fatalf("expected only u3 to be assigned, got %v", prResp.PR.AssignedReviewers)
+	}
+
+	var listResp struct {
+		Unavailability []dto.Unavailability `json:"unavailability"`
+	}
+	doPost(t, client, ts.URL+"/users/removeUnavailability", map[string]any{
+		"user_id": "u2",
+		"id":      addResp.Unavailability.ID,
+	}, http.StatusOK, &listResp)
+	if len(listResp.Unavailability) != 0 {
+		t.Fatalf("expected empty schedule after remove, got %+v", listResp.Unavailability)
+	}
+}
+
+func TestIntegration_ReviewCapacity(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	limit := 1
+	teamBody := dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true, MaxOpenReviews: &limit},
+			{UserID: "u3", Username: "Eve", IsActive: true, MaxOpenReviews: &limit},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-11001",
+		"pull_request_name": "First",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+	if len(prResp.PR.AssignedReviewers) != 2 || prResp.PR.Staffing != nil {
+		t.Fatalf("expected fully staffed PR, got %v %+v", prResp.PR.AssignedReviewers, prResp.PR.Staffing)
+	}
+
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-11002",
+		"pull_request_name": "Second",
+		"author_id":         "u2",
+	}, http.StatusCreated, &prResp)
+	if len(prResp.PR.AssignedReviewers) != 1 || prResp.PR.AssignedReviewers[0] != "u1" {
+		t.Fatalf("expected only u1 to be assigned, got %v", prResp.PR.AssignedReviewers)
+	}
+	if prResp.PR.Staffing == nil || len(prResp.PR.Staffing.AtCapacity) != 1 || prResp.PR.Staffing.AtCapacity[0] != "u3" {
+		t.Fatalf("expected staffing note with u3 at capacity, got %+v", prResp.PR.Staffing)
+	}
+
+	doPost(t, client, ts.URL+"/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-11002",
+		"old_user_id":     "u1",
+		"new_user_id":     "u3",
+	}, http.StatusConflict, nil)
+}
+
+func TestIntegration_CodeOwnersAssignment(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	teamBody := dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "Tom", IsActive: true, Expertise: []string{"db"}},
+			{UserID: "u5", Username: "Kim", IsActive: true},
+		},
+	}
+	doPost(t, client, ts.URL+"/team/add", teamBody, http.StatusCreated, nil)
+
+	doPost(t, client, ts.URL+"/team/setOwnershipRules", map[string]any{
+		"team_name": "backend",
+		"rules": []dto.OwnershipRule{
+			{Pattern: "*", Owners: []string{"u2"}},
+			{Pattern: "/migrations/", Owners: []string{"u5"}},
+		},
+	}, http.StatusOK, nil)
+
+	doPost(t, client, ts.URL+"/team/setOwnershipRules", map[string]any{
+		"team_name": "backend",
+		"rules":     []dto.OwnershipRule{{Pattern: "*.go", Owners: []string{"ghost"}}},
+	}, http.StatusBadRequest, nil)
+
+	var rulesResp struct {
+		Rules []dto.OwnershipRule `json:"rules"`
+	}
+	doGet(t, client, ts.URL+"/team/getOwnershipRules?team_name=backend", http.StatusOK, &rulesResp)
+	if len(rulesResp.Rules) != 2 {
+		t.Fatalf("expected 2 stored rules, got %+v", rulesResp.Rules)
+	}
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-12001",
+		"pull_request_name": "Migration",
+		"author_id":         "u1",
+		"files":             []string{"migrations/11_expertise_ownership.sql"},
+		"labels":            []string{"db"},
+	}, http.StatusCreated, &prResp)
+
+	found := false
+	for _, id := range prResp.PR.AssignedReviewers {
+		if id == "u5" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected code owner u5 among reviewers, got %v", prResp.PR.AssignedReviewers)
+	}
+	if len(prResp.PR.Files) != 1 || len(prResp.PR.Labels) != 1 {
+		t.Fatalf("expected files and labels in response, got %+v", prResp.PR)
+	}
+}
+
+func TestIntegration_ImportCodeowners(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+		},
+	}, http.StatusCreated, nil)
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "platform",
+		Members: []dto.TeamMember{
+			{UserID: "u3", Username: "carol", IsActive: true},
+		},
+	}, http.StatusCreated, nil)
+
+	var importResp struct {
+		Rules         []dto.OwnershipRule `json:"rules"`
+		UnknownOwners []dto.UnknownOwner  `json:"unknown_owners"`
+	}
+	doPost(t, client, ts.URL+"/team/importCodeowners", map[string]string{
+		"team_name": "backend",
+		"content":   "# owners\n* @alice\n/migrations/ @org/platform @ghost\n",
+	}, http.StatusOK, &importResp)
+
+	if len(importResp.Rules) != 2 || importResp.Rules[1].Owners[0] != "u3" {
+		t.Fatalf("unexpected imported rules: %+v", importResp.Rules)
+	}
+	if len(importResp.UnknownOwners) != 1 || importResp.UnknownOwners[0].Handle != "@ghost" || importResp.UnknownOwners[0].Line != 3 {
+		t.Fatalf("expected @ghost reported as unknown, got %+v", importResp.UnknownOwners)
+	}
+
+	doPost(t, client, ts.URL+"/team/importCodeowners", map[string]string{
+		"team_name": "backend",
+		"content":   "!*.go @alice\n",
+	}, http.StatusBadRequest, nil)
+
+	var lookupResp struct {
+		Paths []dto.PathOwners `json:"paths"`
+	}
+	doPost(t, client, ts.URL+"/team/lookupOwners", map[string]any{
+		"team_name": "backend",
+		"paths":     []string{"README.md", "migrations/01_init.sql"},
+	}, http.StatusOK, &lookupResp)
+
+	if len(lookupResp.Paths) != 2 || lookupResp.Paths[0].Owners[0] != "u1" || lookupResp.Paths[1].Pattern != "/migrations/" {
+		t.Fatalf("unexpected lookup result: %+v", lookupResp.Paths)
+	}
+}
+
+func TestIntegration_PartnerTeams(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "platform",
+		Members: []dto.TeamMember{
+			{UserID: "p1", Username: "Kim", IsActive: true},
+		},
+	}, http.StatusCreated, nil)
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+		},
+		PartnerTeams: []string{"platform"},
+	}, http.StatusCreated, nil)
+
+	doPost(t, client, ts.URL+"/team/update", map[string]any{
+		"team_name":     "platform",
+		"partner_teams": []string{"platform"},
+	}, http.StatusBadRequest, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-13001",
+		"pull_request_name": "Tiny team",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	if len(prResp.PR.Reviews) != 2 {
+		t.Fatalf("expected 2 reviewers, got %+v", prResp.PR.Reviews)
+	}
+	sources := map[string]string{}
+	for _, r := range prResp.PR.Reviews {
+		sources[r.UserID] = r.SourceTeam
+	}
+	if sources["u2"] != "backend" || sources["p1"] != "platform" {
+		t.Fatalf("unexpected reviewer sources: %v", sources)
+	}
+}
+
+func TestIntegration_MentorPairing(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	pairing := true
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true, Seniority: "middle"},
+			{UserID: "u3", Username: "Eve", IsActive: true, Seniority: "senior"},
+			{UserID: "u4", Username: "Tom", IsActive: true, Seniority: "junior"},
+			{UserID: "u5", Username: "Kim", IsActive: true, Seniority: "senior"},
+		},
+		MentorPairing: &pairing,
+	}, http.StatusCreated, nil)
+
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "broken",
+		Members: []dto.TeamMember{
+			{UserID: "x1", Username: "X", IsActive: true, Seniority: "lead"},
+		},
+	}, http.StatusBadRequest, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-14001",
+		"pull_request_name": "Pairing",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	senior := ""
+	hasJunior := false
+	for _, id := range prResp.PR.AssignedReviewers {
+		switch id {
+		case "u3", "u5":
+			senior = id
+		case "u4":
+			hasJunior = true
+		}
+	}
+	if senior == "" || !hasJunior {
+		t.Fatalf("expected one senior and one junior, got %v", prResp.PR.AssignedReviewers)
+	}
+
+	var reassignResp struct {
+		ReplacedBy string `json:"replaced_by"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-14001",
+		"old_user_id":     senior,
+	}, http.StatusOK, &reassignResp)
+	if reassignResp.ReplacedBy != "u3" && reassignResp.ReplacedBy != "u5" {
+		t.Fatalf("senior must be replaced by another senior, got %s", reassignResp.ReplacedBy)
+	}
+}
+
+func TestIntegration_PairRules(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "Tom", IsActive: true},
+		},
+	}, http.StatusCreated, nil)
+
+	var ruleResp struct {
+		Rule dto.PairRule `json:"rule"`
+	}
+	doPost(t, client, ts.URL+"/team/addPairRule", map[string]string{
+		"team_name":   "backend",
+		"reviewer_id": "u2",
+		"author_id":   "u1",
+		"kind":        "exclude",
+		"reason":      "same squad",
+	}, http.StatusCreated, &ruleResp)
+	if ruleResp.Rule.ID == 0 || ruleResp.Rule.Kind != "exclude" {
+		t.Fatalf("unexpected rule: %+v", ruleResp.Rule)
+	}
+
+	doPost(t, client, ts.URL+"/team/addPairRule", map[string]string{
+		"team_name":   "backend",
+		"reviewer_id": "u2",
+		"author_id":   "u1",
+		"kind":        "prefer",
+	}, http.StatusBadRequest, nil)
+
+	doPost(t, client, ts.URL+"/team/addPairRule", map[string]string{
+		"team_name":   "backend",
+		"reviewer_id": "u3",
+		"author_id":   "u1",
+		"kind":        "prefer",
+	}, http.StatusCreated, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-15001",
+		"pull_request_name": "Rules",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	reviewers := prResp.PR.AssignedReviewers
+	if len(reviewers) != 2 || !slices.Contains(reviewers, "u3") || slices.Contains(reviewers, "u2") {
+		t.Fatalf("expected preferred u3 and no excluded u2, got %v", reviewers)
+	}
+
+	doPost(t, client, ts.URL+"/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-15001",
+		"old_user_id":     "u3",
+		"new_user_id":     "u2",
+	}, http.StatusConflict, nil)
+
+	var rulesResp struct {
+		TeamName string         `json:"team_name"`
+		Rules    []dto.PairRule `json:"rules"`
+	}
+	doGet(t, client, ts.URL+"/team/getPairRules?team_name=backend", http.StatusOK, &rulesResp)
+	if len(rulesResp.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %+v", rulesResp.Rules)
+	}
+
+	doPost(t, client, ts.URL+"/team/removePairRule", map[string]any{
+		"team_name": "backend",
+		"id":        ruleResp.Rule.ID,
+	}, http.StatusOK, &rulesResp)
+	if len(rulesResp.Rules) != 1 || rulesResp.Rules[0].Kind != "prefer" {
+		t.Fatalf("expected only the prefer rule to remain, got %+v", rulesResp.Rules)
+	}
+
+	doPost(t, client, ts.URL+"/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-15001",
+		"old_user_id":     "u3",
+		"new_user_id":     "u2",
+	}, http.StatusOK, nil)
+}
+
+func TestIntegration_ReviewerRotation(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	maxReviewers := 1
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "Tom", IsActive: true},
+		},
+		ReviewerStrategy: "rotation",
+		MaxReviewers:     &maxReviewers,
+	}, http.StatusCreated, nil)
+
+	var teamResp struct {
+		Team dto.Team `json:"team"`
+	}
+	doPost(t, client, ts.URL+"/team/update", map[string]any{
+		"team_name":       "backend",
+		"rotation_window": 2,
+	}, http.StatusOK, &teamResp)
+	if teamResp.Team.RotationWindow == nil || *teamResp.Team.RotationWindow != 2 {
+		t.Fatalf("unexpected rotation_window: %+v", teamResp.Team.RotationWindow)
+	}
+
+	doPost(t, client, ts.URL+"/team/update", map[string]any{
+		"team_name":       "backend",
+		"rotation_window": -1,
+	}, http.StatusBadRequest, nil)
+
+	var seen []string
+	for i := 1; i <= 3; i++ {
+		var prResp struct {
+			PR dto.PullRequest `json:"pr"`
+		}
+		doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+			"pull_request_id":   fmt.Sprintf("pr-1600%d", i),
+			"pull_request_name": "Rotation",
+			"author_id":         "u1",
+		}, http.StatusCreated, &prResp)
+
+		if len(prResp.PR.AssignedReviewers) != 1 {
+			t.Fatalf("expected one reviewer, got %v", prResp.PR.AssignedReviewers)
+		}
+		reviewer := prResp.PR.AssignedReviewers[0]
+		if slices.Contains(seen, reviewer) {
+			t.Fatalf("reviewer %s repeated within the rotation window, history %v", reviewer, seen)
+		}
+		seen = append(seen, reviewer)
+	}
+}
+
+func TestIntegration_AssignmentExplain(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "Tom", IsActive: true},
+		},
+	}, http.StatusCreated, nil)
+
+	var prResp struct {
+		PR dto.PullRequest `json:"pr"`
+	}
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-17001",
+		"pull_request_name": "Explain",
+		"author_id":         "u1",
+	}, http.StatusCreated, &prResp)
+
+	doPost(t, client, ts.URL+"/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-17001",
+		"old_user_id":     prResp.PR.AssignedReviewers[0],
+	}, http.StatusOK, nil)
+
+	var explainResp struct {
+		PullRequestID string           `json:"pull_request_id"`
+		Assignments   []dto.Assignment `json:"assignments"`
+	}
+	doGet(t, client, ts.URL+"/pullRequest/assignmentExplain?pull_request_id=pr-17001", http.StatusOK, &explainResp)
+
+	if len(explainResp.Assignments) != 2 {
+		t.Fatalf("expected create and reassign records, got %+v", explainResp.Assignments)
+	}
+	created := explainResp.Assignments[0]
+	if created.Operation != "create" || len(created.Decisions) != 1 {
+		t.Fatalf("unexpected create record: %+v", created)
+	}
+	d := created.Decisions[0]
+	if d.Strategy != "random" || len(d.Candidates) != 3 || !slices.Equal(d.Selected, prResp.PR.AssignedReviewers) {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+	for _, a := range explainResp.Assignments {
+		for _, d := range a.Decisions {
+			if !d.Reproducible {
+				t.Fatalf("%s decision not reproducible: %+v", a.Operation, d)
+			}
+		}
+	}
+	if explainResp.Assignments[1].Operation != "reassign" {
+		t.Fatalf("expected reassign record, got %+v", explainResp.Assignments[1])
+	}
+
+	doGet(t, client, ts.URL+"/pullRequest/assignmentExplain?pull_request_id=missing", http.StatusNotFound, nil)
+}
+
+type recordingEventBus struct {
+	mu     sync.Mutex
+	events []domain.Envelope
+}
+
+func (b *recordingEventBus) Publish(ctx context.Context, env domain.Envelope) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, env)
+	return nil
+}
+
+func TestIntegration_OutboxIsTransactional(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	log, err := logging.NewLogger()
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	ctx := context.Background()
+	uow := pg.NewTxManager(db)
+	outbox := pg.NewOutbox(db)
+	teamRepo := pg.NewTeamRepository(db)
+	userRepo := pg.NewUserRepository(db)
+	teamSvc := team.NewService(uow, teamRepo, userRepo, nil, outbox)
+
+	errAbort := errors.New("abort")
+	err = uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := outbox.Publish(ctx, domain.TeamCreated{TeamName: "ghost"}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_outbox`).Scan(&count); err != nil {
+		t.Fatalf("count outbox: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected rolled back tx to leave no events, got %d", count)
+	}
+
+	if _, err := teamSvc.AddTeam(domain.WithActor(ctx, "admin"), team.Team{
+		Name:    "backend",
+		Members: []team.Member{{ID: "u1", Username: "Alice", IsActive: true}},
+	}); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_outbox WHERE dispatched_at IS NULL`).Scan(&count); err != nil {
+		t.Fatalf("count outbox: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 pending event, got %d", count)
+	}
+
+	bus := &recordingEventBus{}
+	relay := async.NewOutboxRelay(uow, outbox, bus, async.FanoutBus{}, async.DefaultOutboxRetryPolicy(), time.Second, 10, log)
+
+	n, err := relay.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if n != 1 || len(bus.events) != 1 {
+		t.Fatalf("unexpected dispatch: n=%d events=%+v", n, bus.events)
+	}
+	env := bus.events[0]
+	if env.Type != "team.created" || env.Version != 1 || env.Actor != "admin" || env.ID == "" || env.OccurredAt.IsZero() {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+	var created domain.TeamCreated
+	if err := json.Unmarshal(env.Payload, &created); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if created.TeamName != "backend" || !slices.Equal(created.MemberIDs, []string{"u1"}) {
+		t.Fatalf("unexpected payload: %s", env.Payload)
+	}
+
+	n, err = relay.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("second dispatch: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expected nothing left to dispatch, got %d", n)
+	}
+}
+
+type eventBusFunc func(ctx context.Context, env domain.Envelope) error
+
+func (f eventBusFunc) Publish(ctx context.Context, env domain.Envelope) error {
+	return f(ctx, env)
+}
+
+func TestIntegration_OutboxRetriesInAggregateOrder(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	log, err := logging.NewLogger()
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	ctx := context.Background()
+	uow := pg.NewTxManager(db)
+	outbox := pg.NewOutbox(db)
+	webhookRepo := pg.NewWebhookRepository(db)
+
+	for _, e := range []domain.Event{
+		domain.TeamCreated{TeamName: "alpha"},
+		domain.TeamUpdated{TeamName: "alpha"},
+		domain.TeamCreated{TeamName: "beta"},
+	} {
+		if err := outbox.Publish(ctx, e); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	var (
+		healthy bool
+		seen    []string
+	)
+	bus := eventBusFunc(func(ctx context.Context, env domain.Envelope) error {
+		var p struct {
+			TeamName string `json:"team_name"`
+		}
+		_ = json.Unmarshal(env.Payload, &p)
+		if !healthy && env.Type == "team.created" && p.TeamName == "alpha" {
+			// A missing subscription violates the foreign key and aborts the transaction.
+			return webhookRepo.EnqueueDelivery(ctx, 999, env)
+		}
+		seen = append(seen, env.Type+":"+p.TeamName)
+		return nil
+	})
+	relay := async.NewOutboxRelay(uow, outbox, bus, async.FanoutBus{}, domain.RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    100 * time.Millisecond,
+	}, time.Second, 10, log)
+
+	n, err := relay.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if n != 1 || !slices.Equal(seen, []string{"team.created:beta"}) {
+		t.Fatalf("expected only the unrelated aggregate to be delivered, n=%d seen=%v", n, seen)
+	}
+
+	var (
+		attempts int
+		lastErr  sql.NullString
+	)
+	if err := db.QueryRowContext(ctx,
+		`SELECT attempts, last_error FROM event_outbox WHERE id = 1`,
+	).Scan(&attempts, &lastErr); err != nil {
+		t.Fatalf("read outbox: %v", err)
+	}
+	if attempts != 1 || !lastErr.Valid {
+		t.Fatalf("expected failure to be recorded, attempts=%d last_error=%v", attempts, lastErr)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	n, err = relay.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("second dispatch: %v", err)
+	}
+	if n != 1 || !slices.Equal(seen, []string{"team.created:beta", "team.updated:alpha"}) {
+		t.Fatalf("later alpha event must be delivered once the failed one is dead-lettered, n=%d seen=%v", n, seen)
+	}
+
+	var dead bool
+	if err := db.QueryRowContext(ctx,
+		`SELECT dead_at IS NOT NULL FROM event_outbox WHERE id = 1`,
+	).Scan(&dead); err != nil {
+		t.Fatalf("read outbox: %v", err)
+	}
+	if !dead {
+		t.Fatalf("expected event to be dead-lettered after max attempts")
+	}
+
+	if _, err := db.ExecContext(ctx,
+		`UPDATE event_outbox SET dead_at = NULL, attempts = 0, next_attempt_at = NOW() WHERE id = 1`,
+	); err != nil {
+		t.Fatalf("requeue: %v", err)
+	}
+	healthy = true
+
+	n, err = relay.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("third dispatch: %v", err)
+	}
+	want := []string{"team.created:beta", "team.updated:alpha", "team.created:alpha"}
+	if n != 1 || !slices.Equal(seen, want) {
+		t.Fatalf("expected requeued dead letter to be delivered, n=%d seen=%v", n, seen)
+	}
+}
+
+func TestIntegration_LiveEventsFollowCommit(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	log, err := logging.NewLogger()
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	ctx := context.Background()
+	uow := pg.NewTxManager(db)
+	outbox := pg.NewOutbox(db)
+	eventLogRepo := pg.NewEventLogRepository(db)
+	eventLogSvc := eventlog.NewService(uow, eventLogRepo, async.FanoutBus{})
+
+	if err := outbox.Publish(ctx, domain.PRCreated{PullRequestID: "pr-1", TeamName: "backend"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	var liveErrs []error
+	live := eventBusFunc(func(ctx context.Context, env domain.Envelope) error {
+		// Runs outside the relay transaction: the event must already be visible.
+		_, err := eventLogRepo.SeqOf(context.Background(), env.ID)
+		liveErrs = append(liveErrs, err)
+		return nil
+	})
+	relay := async.NewOutboxRelay(uow, outbox, eventLogSvc, live, async.DefaultOutboxRetryPolicy(), time.Second, 10, log)
+
+	n, err := relay.DispatchPending(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("dispatch: n=%d err=%v", n, err)
+	}
+	if len(liveErrs) != 1 || liveErrs[0] != nil {
+		t.Fatalf("expected live subscriber to see a committed event, got %v", liveErrs)
+	}
+}
+
+func TestIntegration_Webhooks(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	type received struct {
+		Event     string
+		Signature string
+		Body      []byte
+	}
+	var (
+		mu       sync.Mutex
+		got      []received
+		requests int
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		got = append(got, received{
+			Event:     r.Header.Get("X-Webhook-Event"),
+			Signature: r.Header.Get(webhook.SignatureHeader),
+			Body:      body,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	doPost(t, client, ts.URL+"/webhooks/subscribe", map[string]any{
+		"url":         receiver.URL,
+		"secret":      "s3cr3t",
+		"event_types": []string{"pr.created"},
+	}, http.StatusUnauthorized, nil)
+	doGet(t, client, ts.URL+"/webhooks/list", http.StatusUnauthorized, nil)
+
+	doPostWithHeaders(t, client, ts.URL+"/webhooks/subscribe", adminHeaders, map[string]any{
+		"url":         receiver.URL,
+		"secret":      "s3cr3t",
+		"event_types": []string{"pr.unknown"},
+	}, http.StatusBadRequest, nil)
+
+	var subResp struct {
+		Subscription dto.WebhookSubscription `json:"subscription"`
+	}
+	doPostWithHeaders(t, client, ts.URL+"/webhooks/subscribe", adminHeaders, map[string]any{
+		"url":         receiver.URL,
+		"secret":      "s3cr3t",
+		"event_types": []string{"team.created"},
+	}, http.StatusCreated, &subResp)
+
+	doPost(t, client, ts.URL+"/team/add", dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+		},
+	}, http.StatusCreated, nil)
+
+	doPost(t, client, ts.URL+"/users/setIsActive", map[string]any{
+		"user_id":   "u1",
+		"is_active": false,
+	}, http.StatusOK, nil)
+
+	var deliveries struct {
+		Deliveries []dto.WebhookDelivery `json:"deliveries"`
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		doGetWithHeaders(t, client, fmt.Sprintf("%s/webhooks/deliveries?subscription_id=%d", ts.URL, subResp.Subscription.ID), adminHeaders, http.StatusOK, &deliveries)
+		if len(deliveries.Deliveries) == 1 && deliveries.Deliveries[0].Status == "delivered" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("webhook not delivered: %+v", deliveries.Deliveries)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	d := deliveries.Deliveries[0]
+	if d.Event.Type != "team.created" || d.Attempts != 2 || d.ResponseStatus != http.StatusNoContent {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0].Event != "team.created" {
+		t.Fatalf("unexpected requests: %+v", got)
+	}
+	if got[0].Signature != webhook.Sign("s3cr3t", got[0].Body) {
+		t.Fatalf("invalid signature %q", got[0].Signature)
+	}
+	var body dto.Event
+	if err := json.Unmarshal(got[0].Body, &body); err != nil {
+		t.Fatalf("decode body %s: %v", got[0].Body, err)
+	}
+	var created domain.TeamCreated
+	if err := json.Unmarshal(body.Payload, &created); err != nil || created.TeamName != "backend" {
+		t.Fatalf("unexpected body %s: %v", got[0].Body, err)
+	}
+	if body.ID != d.Event.ID || body.Type != "team.created" || body.Version != 1 {
+		t.Fatalf("unexpected envelope %+v", body)
+	}
+
+	var list struct {
+		Subscriptions []dto.WebhookSubscription `json:"subscriptions"`
+	}
+	doPostWithHeaders(t, client, ts.URL+"/webhooks/unsubscribe", adminHeaders, map[string]any{
+		"subscription_id": subResp.Subscription.ID,
+	}, http.StatusOK, &list)
+	if len(list.Subscriptions) != 0 {
+		t.Fatalf("expected no subscriptions, got %+v", list.Subscriptions)
+	}
+	doPostWithHeaders(t, client, ts.URL+"/webhooks/unsubscribe", adminHeaders, map[string]any{
+		"subscription_id": subResp.Subscription.ID,
+	}, http.StatusNotFound, nil)
+}
+
+func waitForEvents(t *testing.T, client *http.Client, url string, n int) dto.EventPage {
+	t.Helper()
+
+	var page dto.EventPage
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		doGet(t, client, url, http.StatusOK, &page)
+		if len(page.Events) >= n {
+			return page
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d events at %s, got %+v", n, url, page.Events)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestIntegration_EventLog(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	start := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+	doPostWithHeaders(t, client, ts.URL+"/team/add", map[string]string{"X-Actor": "alice"}, dto.Team{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+		},
+	}, http.StatusCreated, nil)
+
+	for _, id := range []string{"pr-24001", "pr-24002", "pr-24003"} {
+		doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+			"pull_request_id":   id,
+			"pull_request_name": "Log " + id,
+			"author_id":         "u1",
+		}, http.StatusCreated, nil)
+	}
+	doPost(t, client, ts.URL+"/pullRequest/merge", map[string]string{
+		"pull_request_id": "pr-24002",
+	}, http.StatusOK, nil)
+
+	all := waitForEvents(t, client, ts.URL+"/events?since="+start, 5)
+	if all.Events[0].Type != "team.created" || all.Events[0].Actor != "alice" || all.Events[1].Actor != "system" {
+		t.Fatalf("unexpected log head %+v", all.Events[:2])
+	}
+	for i := 1; i < len(all.Events); i++ {
+		if all.Events[i].Seq <= all.Events[i-1].Seq {
+			t.Fatalf("events must be ordered by seq: %+v", all.Events)
+		}
+	}
+
+	var ids []string
+	url := ts.URL + "/events?type=pr.created&limit=2"
+	for i := 0; ; i++ {
+		var page dto.EventPage
+		doGet(t, client, url, http.StatusOK, &page)
+		for _, e := range page.Events {
+			var created domain.PRCreated
+			if err := json.Unmarshal(e.Payload, &created); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			ids = append(ids, created.PullRequestID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if i > 3 {
+			t.Fatalf("pagination does not terminate")
+		}
+		url = ts.URL + "/events?type=pr.created&limit=2&cursor=" + page.NextCursor
+	}
+	if !slices.Equal(ids, []string{"pr-24001", "pr-24002", "pr-24003"}) {
+		t.Fatalf("unexpected paged events %v", ids)
+	}
+
+	var byPR dto.EventPage
+	doGet(t, client, ts.URL+"/events?pull_request_id=pr-24002", http.StatusOK, &byPR)
+	if len(byPR.Events) != 2 || byPR.Events[0].Type != "pr.created" || byPR.Events[1].Type != "pr.merged" {
+		t.Fatalf("unexpected events for pr-24002 %+v", byPR.Events)
+	}
+
+	doGet(t, client, ts.URL+"/events?cursor=@@", http.StatusBadRequest, nil)
+	doGet(t, client, ts.URL+"/events?since=yesterday", http.StatusBadRequest, nil)
+
+	var requests int
+	var mu sync.Mutex
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	doPostWithHeaders(t, client, ts.URL+"/webhooks/subscribe", adminHeaders, map[string]any{
+		"url":         receiver.URL,
+		"secret":      "s3cr3t",
+		"event_types": []string{"pr.merged"},
+	}, http.StatusCreated, nil)
+
+	replay := map[string]any{"from_seq": all.Events[0].Seq, "type": "pr.merged"}
+	doPost(t, client, ts.URL+"/admin/events/replay", replay, http.StatusUnauthorized, nil)
+
+	var res dto.ReplayResult
+	doPostWithHeaders(t, client, ts.URL+"/admin/events/replay", adminHeaders, replay, http.StatusOK, &res)
+	if res.Replayed != 1 || res.FirstSeq != byPR.Events[1].Seq {
+		t.Fatalf("unexpected replay result %+v", res)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := requests
+		mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("replayed event was not delivered, requests=%d", n)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	var after dto.EventPage
+	doGet(t, client, ts.URL+"/events?since="+start, http.StatusOK, &after)
+	if len(after.Events) != len(all.Events) {
+		t.Fatalf("replay must not append to the log: before %d, after %d", len(all.Events), len(after.Events))
+	}
+}
+
+type sseFrame struct {
+	ID      string
+	Event   string
+	Data    string
+	Comment string
+}
+
+func openEventStream(t *testing.T, url, lastEventID string) (*http.Response, <-chan sseFrame) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream %s: %v", url, err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		resp.Body.Close()
+		t.Fatalf("unexpected stream response %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	frames := make(chan sseFrame, 64)
+	go func() {
+		defer close(frames)
+		var f sseFrame
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case line == "":
+				if f != (sseFrame{}) {
+					frames <- f
+				}
+				f = sseFrame{}
+			case strings.HasPrefix(line, ":"):
+				f.Comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				f.ID = line[len("id: "):]
+			case strings.HasPrefix(line, "event: "):
+				f.Event = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				f.Data = line[len("data: "):]
+			}
+		}
+	}()
+	return resp, frames
+}
+
+func nextStreamFrame(t *testing.T, frames <-chan sseFrame, match func(sseFrame) bool) sseFrame {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case f, ok := <-frames:
+			if !ok {
+				t.Fatalf("stream closed unexpectedly")
+			}
+			if match(f) {
+				return f
+			}
+		case <-timeout:
+			t.Fatalf("no matching stream frame within timeout")
+		}
+	}
+}
+
+func isStreamEvent(f sseFrame) bool { return f.Event != "" }
+
+func TestIntegration_EventStream(t *testing.T) {
+	ts, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	for _, team := range []dto.Team{
+		{TeamName: "backend", Members: []dto.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+		}},
+		{TeamName: "frontend", Members: []dto.TeamMember{
+			{UserID: "f1", Username: "Carol", IsActive: true},
+			{UserID: "f2", Username: "Dave", IsActive: true},
+		}},
+	} {
+		doPost(t, client, ts.URL+"/team/add", team, http.StatusCreated, nil)
+	}
+
+	resp, frames := openEventStream(t, ts.URL+"/events/stream?team_name=backend", "")
+
+	for _, p := range []map[string]string{
+		{"pull_request_id": "pr-25001", "pull_request_name": "Stream", "author_id": "u1"},
+		{"pull_request_id": "pr-25002", "pull_request_name": "Other team", "author_id": "f1"},
+	} {
+		doPost(t, client, ts.URL+"/pullRequest/create", p, http.StatusCreated, nil)
+	}
+	doPost(t, client, ts.URL+"/pullRequest/merge", map[string]string{
+		"pull_request_id": "pr-25001",
+	}, http.StatusOK, nil)
+
+	created := nextStreamFrame(t, frames, isStreamEvent)
+	merged := nextStreamFrame(t, frames, isStreamEvent)
+	if created.Event != "pr.created" || merged.Event != "pr.merged" {
+		t.Fatalf("unexpected stream events %+v %+v", created, merged)
+	}
+
+	var ev dto.Event
+	if err := json.Unmarshal([]byte(merged.Data), &ev); err != nil {
+		t.Fatalf("decode stream event: %v", err)
+	}
+	var payload domain.PRMerged
+	if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if ev.ID != merged.ID || payload.PullRequestID != "pr-25001" || payload.TeamName != "backend" || !slices.Equal(payload.Reviewers, []string{"u2"}) {
+		t.Fatalf("unexpected merged event %+v %+v", ev, payload)
+	}
+
+	nextStreamFrame(t, frames, func(f sseFrame) bool { return f.Comment == "heartbeat" })
+	resp.Body.Close()
+
+	doPost(t, client, ts.URL+"/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-25003", "pull_request_name": "Missed", "author_id": "u1",
+	}, http.StatusCreated, nil)
+	waitForEvents(t, client, ts.URL+"/events?pull_request_id=pr-25003", 1)
+
+	resp, frames = openEventStream(t, ts.URL+"/events/stream?user_id=u2", merged.ID)
+	defer resp.Body.Close()
+
+	missed := nextStreamFrame(t, frames, isStreamEvent)
+	if missed.Event != "pr.created" || !strings.Contains(missed.Data, "pr-25003") {
+		t.Fatalf("expected missed pr-25003 after resume, got %+v", missed)
+	}
+
+	doPost(t, client, ts.URL+"/pullRequest/removeReviewer", map[string]string{
+		"pull_request_id": "pr-25003", "user_id": "u2",
+	}, http.StatusOK, nil)
+
+	removed := nextStreamFrame(t, frames, isStreamEvent)
+	if removed.Event != "pr.reviewer_removed" || !strings.Contains(removed.Data, "pr-25003") {
+		t.Fatalf("unexpected live event after resume %+v", removed)
+	}
+}